@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"github.com/chapsuk/grace"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/environment"
+	ll "go.sport-news/internal/logger"
+	"go.sport-news/internal/migration"
+	"go.uber.org/zap"
+)
+
+//nolint:gochecknoglobals
+const (
+	version = "unknown"
+)
+
+// migrate runs one-off data migration: migrate [OPTIONS] <name>.
+func main() {
+	cfg, args := config.MustLoadWithArgs()
+
+	logger := ll.MustLoad(version, cfg.Env, cfg.Logger.Level)
+	defer logger.Sync() //nolint:errcheck
+
+	if len(args) != 1 {
+		logger.Fatal("usage: migrate [OPTIONS] <name>", zap.Strings("migrations", migration.Names()))
+	}
+
+	ctx := grace.ShutdownContext(context.Background())
+	ctx = environment.CtxWithEnv(ctx, cfg.Env)
+
+	db := database.MustLoad(ctx, logger, cfg.Mongo)
+	defer db.Disconnect(ctx)
+
+	if err := migration.Run(ctx, logger, db, cfg.Parser, args[0]); err != nil {
+		logger.Fatal("migration failed", zap.Error(err))
+	}
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	"github.com/chapsuk/grace"
-	"github.com/patrickmn/go-cache"
+	"go.sport-news/internal/cache"
 	"go.sport-news/internal/config"
 	v1 "go.sport-news/internal/controller/http/v1"
 	"go.sport-news/internal/database"
@@ -12,6 +12,7 @@ import (
 	ll "go.sport-news/internal/logger"
 	"go.sport-news/internal/repository"
 	"go.sport-news/internal/scheduler"
+	"go.sport-news/internal/tracing"
 	"go.uber.org/zap"
 	"time"
 )
@@ -22,7 +23,7 @@ const (
 )
 
 func main() {
-	cfg := config.MustLoad()
+	cfg, args := config.MustLoadWithArgs()
 
 	logger := ll.MustLoad(version, cfg.Env, cfg.Logger.Level)
 	defer logger.Sync() //nolint:errcheck
@@ -30,17 +31,61 @@ func main() {
 	ctx := grace.ShutdownContext(context.Background())
 	ctx = environment.CtxWithEnv(ctx, cfg.Env)
 
+	shutdownTracing := tracing.MustLoad(ctx, logger, cfg.Tracing, version)
+	defer func() {
+		// the spans are flushed after the shutdown context is done
+		sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(sctx); err != nil {
+			logger.Error("failed flush spans", zap.Error(err))
+		}
+	}()
+
 	db := database.MustLoad(ctx, logger, cfg.Mongo)
 	defer db.Disconnect(ctx)
 
+	switch {
+	case len(args) == 1 && args[0] == "backfill":
+		if err := scheduler.Backfill(ctx, logger, cfg.Parser, cfg.Backfill, db); err != nil {
+			logger.Fatal("backfill failed", zap.Error(err))
+		}
+		return
+	case len(args) > 0:
+		logger.Fatal("usage: sport-news [OPTIONS] [backfill]", zap.Strings("args", args))
+	}
+
+	// the interface stays nil when the parser is off
+	var (
+		jobs  scheduler.Manager
+		teams []string
+	)
 	if cfg.Parser.Enable == 1 {
-		scheduler.New(logger, cfg.Parser, db)
+		s := scheduler.New(logger, cfg.Parser, db)
+		defer s.Shutdown() //nolint:errcheck
+		jobs = s
+		for _, j := range s.Jobs() {
+			teams = append(teams, j.Team)
+		}
 	}
 
 	httpServer := http.New(
 		v1.NewNewsController(
 			repository.NewNewsRepository(db),
-			cache.New(1*time.Minute, 5*time.Minute),
+			cache.MustLoad(ctx, logger, cfg.Cache),
+			cfg.Cache.TTL,
+			logger,
+		),
+		v1.NewAdminController(
+			repository.NewIngestRepository(db),
+			jobs,
+			cfg.HTTP.AdminToken,
+			logger,
+		),
+		v1.NewHealthController(
+			db,
+			repository.NewIngestRepository(db),
+			teams,
+			cfg.HTTP.ReadyIngestAge,
 			logger,
 		),
 		logger,
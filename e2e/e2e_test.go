@@ -36,10 +36,11 @@ func Test_e2e(t *testing.T) {
 	}
 
 	err = scheduler.New(logger, config.Parser{
+		Team:    entity.DefaultTeamId,
 		URL:     fmt.Sprintf("http://0.0.0.0:%d", cfg.HTTP.ExternalPort),
 		Count:   1,
 		JobTime: time.Minute,
-	}, db).RunNow()
+	}, db).RunNow(entity.DefaultTeamId)
 
 	if !assert.Nil(t, err) {
 		logger.Fatal("failed run job", zap.Error(err))
@@ -48,14 +49,14 @@ func Test_e2e(t *testing.T) {
 
 	<-time.NewTimer(time.Second).C
 
-	list, err := repo.GetTeamNews(ctx, entity.DefaultTeamId)
+	list, err := repo.GetTeamNews(ctx, entity.DefaultTeamId, repository.NewsFilter{}, repository.Page{})
 	if err != nil {
 		t.Error(err)
 	}
 
-	assert.Len(t, *list, 1)
+	assert.Len(t, list.Articles, 1)
 
-	for _, article := range *list {
+	for _, article := range list.Articles {
 		_, err := http.Post(fmt.Sprintf("http://0.0.0.0:%d/v1/cache-flush", cfg.HTTP.Port), "application/json", nil)
 		if err != nil {
 			t.Error(err)
@@ -0,0 +1,55 @@
+// Package cache keeps encoded API responses in the process memory or in redis shared by the replicas.
+package cache
+
+import (
+	"context"
+	"go.sport-news/internal/config"
+	"go.uber.org/zap"
+	"net/url"
+	"time"
+)
+
+// Drivers of the cache.
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+// Cache of the encoded values.
+type Cache interface {
+	// Get return the value, found is false when the key is missing or expired.
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set the value of the key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Flush remove all keys of the cache.
+	Flush(ctx context.Context) error
+}
+
+// MustLoad return cache of the configured driver without errors.
+func MustLoad(ctx context.Context, logger *zap.Logger, cfg config.Cache) Cache {
+	switch cfg.Driver {
+	case DriverMemory, "":
+		return NewMemory(cfg.Cleanup)
+	case DriverRedis:
+		r, err := NewRedisFromURL(cfg.RedisURL, cfg.Prefix)
+		if err != nil {
+			logger.Fatal("failed to parse redis url", zap.Error(err))
+		}
+		if err = r.Ping(ctx); err != nil {
+			logger.Fatal("failed to ping redis", zap.Error(err))
+		}
+		logger.Info("cache is shared in redis", zap.String("prefix", cfg.Prefix))
+
+		return r
+	default:
+		logger.Fatal("unknown cache driver", zap.String("driver", cfg.Driver))
+	}
+
+	return nil
+}
+
+// Key of the endpoint response, params are path vars and canonical query params.
+// Encoded params are sorted, so their order doesn't matter.
+func Key(endpoint string, params url.Values) string {
+	return endpoint + "?" + params.Encode()
+}
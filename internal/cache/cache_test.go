@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	a := Key("news", url.Values{"team": {"t94"}, "limit": {"10"}, "type": {"a,b"}})
+	b := Key("news", url.Values{"type": {"a,b"}, "limit": {"10"}, "team": {"t94"}})
+
+	assert.Equal(t, "news?limit=10&team=t94&type=a%2Cb", a)
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, Key("search", url.Values{"team": {"t94"}, "limit": {"10"}, "type": {"a,b"}}))
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	gocache "github.com/patrickmn/go-cache"
+	"time"
+)
+
+// Memory cache of the process, every replica has its own.
+type Memory struct {
+	c *gocache.Cache
+}
+
+// NewMemory return memory cache, expired items are removed every cleanup.
+func NewMemory(cleanup time.Duration) *Memory {
+	return &Memory{c: gocache.New(gocache.NoExpiration, cleanup)}
+}
+
+// Get return the value of the key.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	v, found := m.c.Get(key)
+	if !found {
+		return nil, false, nil
+	}
+
+	return v.([]byte), true, nil
+}
+
+// Set the value of the key, ttl 0 keeps it until flush.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = gocache.NoExpiration
+	}
+	m.c.Set(key, value, ttl)
+
+	return nil
+}
+
+// Flush remove all items.
+func (m *Memory) Flush(_ context.Context) error {
+	m.c.Flush()
+
+	return nil
+}
+
+// Len count of the items, expired ones are counted until cleanup.
+func (m *Memory) Len() int {
+	return m.c.ItemCount()
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(time.Minute)
+
+	_, found, err := m.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, m.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, m.Set(ctx, "b", []byte("2"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+
+	v, found, err := m.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("1"), v)
+
+	_, found, err = m.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, found, "expired")
+
+	require.NoError(t, m.Flush(ctx))
+	assert.Equal(t, 0, m.Len())
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	r := NewRedis(client, "sport-news:cache:")
+
+	_, found, err := r.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, r.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, r.Set(ctx, "b", []byte("2"), time.Second))
+	assert.True(t, mr.Exists("sport-news:cache:a"))
+
+	v, found, err := r.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("1"), v)
+
+	mr.FastForward(2 * time.Second)
+	_, found, err = r.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, found, "expired")
+
+	mr.Close()
+	_, _, err = r.Get(ctx, "a")
+	assert.Error(t, err)
+}
+
+func TestRedis_Flush(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	r := NewRedis(client, "sport-news:cache:")
+
+	// more keys than one scan batch
+	for i := 0; i < scanCount+10; i++ {
+		require.NoError(t, r.Set(ctx, strconv.Itoa(i), []byte("1"), time.Minute))
+	}
+	require.NoError(t, mr.Set("other", "1"))
+
+	require.NoError(t, r.Flush(ctx))
+
+	assert.Equal(t, []string{"other"}, mr.Keys())
+}
+
+func TestNewRedisFromURL(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	r, err := NewRedisFromURL("redis://"+mr.Addr()+"/0", "p:")
+	require.NoError(t, err)
+	assert.NoError(t, r.Ping(context.Background()))
+
+	_, err = NewRedisFromURL("http://localhost", "p:")
+	assert.Error(t, err)
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/jessevdk/go-flags"
+	"go.sport-news/internal/entity"
 	"go.sport-news/internal/environment"
 	"os"
 	"time"
@@ -17,16 +19,96 @@ type (
 		HTTP   Http            `yml:"http" env-namespace:"HTTP" namespace:"http" group:"Http options"`
 		Logger Logger          `yml:"logger" env-namespace:"LOGGER"  namespace:"logger"       group:"Logger options"`
 		Mongo  Mongo           `yml:"mongo" env-namespace:"MONGO"   namespace:"mongo"      group:"Mongodb options"`
+		// Backfill options of the backfill command.
+		Backfill Backfill `yml:"backfill" env-namespace:"BACKFILL" namespace:"backfill" group:"Backfill options"`
+		// Tracing OpenTelemetry options.
+		Tracing Tracing `yml:"tracing" env-namespace:"TRACING" namespace:"tracing" group:"Tracing options"`
+		// Cache of the API responses.
+		Cache Cache `yml:"cache" env-namespace:"CACHE" namespace:"cache" group:"Cache options"`
+	}
+	// Cache of the API responses, the redis one is shared by the replicas.
+	Cache struct {
+		Driver   string        `yml:"driver" env:"DRIVER" long:"driver" description:"Cache driver: memory, redis" default:"memory"`
+		RedisURL string        `yml:"redis_url" env:"REDIS_URL" long:"redis-url" description:"Redis url of the redis driver" default:"redis://localhost:6379/0"`
+		Prefix   string        `yml:"prefix" env:"PREFIX" long:"prefix" description:"Prefix of the redis keys, flush removes only them" default:"sport-news:cache:"`
+		Cleanup  time.Duration `yml:"cleanup" env:"CLEANUP" long:"cleanup" description:"Interval of removing expired items of the memory driver" default:"5m"`
+		TTL      CacheTTL      `yml:"ttl" env-namespace:"TTL" namespace:"ttl" group:"Cache TTL options"`
+	}
+	// CacheTTL how long responses of every endpoint are cached.
+	CacheTTL struct {
+		List       time.Duration `yml:"list" env:"LIST" long:"list" description:"TTL of the news list" default:"1m"`
+		Search     time.Duration `yml:"search" env:"SEARCH" long:"search" description:"TTL of the news search" default:"1m"`
+		Article    time.Duration `yml:"article" env:"ARTICLE" long:"article" description:"TTL of one article" default:"1m"`
+		Categories time.Duration `yml:"categories" env:"CATEGORIES" long:"categories" description:"TTL of the team categories" default:"1m"`
+	}
+	// Tracing OpenTelemetry export of the spans.
+	Tracing struct {
+		Exporter    string  `yml:"exporter" env:"EXPORTER" long:"exporter" description:"Span exporter: none, stdout, otlp" default:"none"`
+		Endpoint    string  `yml:"endpoint" env:"ENDPOINT" long:"endpoint" description:"OTLP HTTP endpoint, host:port" default:"localhost:4318"`
+		Insecure    int8    `yml:"insecure" env:"INSECURE" long:"insecure" description:"Export to the OTLP endpoint without TLS" default:"1"`
+		SampleRatio float64 `yml:"sample_ratio" env:"SAMPLE_RATIO" long:"sample-ratio" description:"Ratio of the sampled traces" default:"1"`
+		ServiceName string  `yml:"service_name" env:"SERVICE_NAME" long:"service-name" description:"Service name of the spans" default:"sport-news"`
+	}
+	// Backfill re-fetches details of the stored articles, progress is kept per team and filter.
+	Backfill struct {
+		Team        string `yml:"team" env:"TEAM" long:"team" description:"Backfill only this team, all sources when empty"`
+		ExternalIds []int  `yml:"external_ids" env:"EXTERNAL_IDS" env-delim:"," long:"external-id" description:"Backfill only these external ids"`
+		BatchSize   int    `yml:"batch_size" env:"BATCH_SIZE" long:"batch-size" description:"Articles fetched between saves of the progress" default:"100"`
+		DryRun      int8   `yml:"dry_run" env:"DRY_RUN" long:"dry-run" description:"Log changed fields instead of saving articles" default:"0"`
+		Restart     int8   `yml:"restart" env:"RESTART" long:"restart" description:"Start from the first article instead of the saved progress" default:"0"`
 	}
 	Mongo struct {
 		URL        string `yml:"url" env:"URL" long:"url" description:"Mongodb url" default:"mongodb://localhost:27017"  `
 		Collection string `yml:"collection" env:"C_NAME" long:"collection-name" description:"Mongodb collection name" default:"sport-news"  `
+		// IngestRunsTTL how long the history of the scheduler runs is kept.
+		IngestRunsTTL time.Duration `yml:"ingest_runs_ttl" env:"INGEST_RUNS_TTL" long:"ingest-runs-ttl" description:"How long ingest runs are kept, 0 keeps them forever" default:"720h"`
 	}
 	Parser struct {
-		Enable  int8          `yml:"enable" env:"ENABLE" long:"enable" description:"Enable parsing monde" default:"1"`
-		URL     string        `yml:"url" env:"URL" long:"url" description:"Parser url" default:"https://www.htafc.com/api/incrowd"`
-		Count   int           `yml:"count" env:"COUNT" long:"count"  description:"Count rows from url" default:"50"`
-		JobTime time.Duration `yml:"time" env:"JOB_TIME" long:"job-time" description:"Job parser timer" default:"30s"`
+		Enable   int8          `yml:"enable" env:"ENABLE" long:"enable" description:"Enable parsing monde" default:"1"`
+		Team     string        `yml:"team" env:"TEAM" long:"team" description:"Team id of the default source" default:"t94"`
+		Provider string        `yml:"provider" env:"PROVIDER" long:"provider" description:"Feed provider of the default source: incrowd, rss, atom" default:"incrowd"`
+		URL      string        `yml:"url" env:"URL" long:"url" description:"Parser url" default:"https://www.htafc.com/api/incrowd"`
+		Count    int           `yml:"count" env:"COUNT" long:"count"  description:"Count rows from url" default:"50"`
+		JobTime  time.Duration `yml:"time" env:"JOB_TIME" long:"job-time" description:"Job parser timer" default:"30s"`
+		// RetractAfter window after which an article missing from the feed is retracted, 0 disables it.
+		RetractAfter time.Duration `yml:"retract_after" env:"RETRACT_AFTER" long:"retract-after" description:"Retract articles missing from feed for this long, 0 disables" default:"1h"`
+		// Workers max article detail fetches at once per source.
+		Workers int `yml:"workers" env:"WORKERS" long:"workers" description:"Max concurrent article detail fetches per source" default:"4"`
+		// RunTimeout max time of one ingest run with the list and all article details, 0 disables it.
+		RunTimeout time.Duration `yml:"run_timeout" env:"RUN_TIMEOUT" long:"run-timeout" description:"Max time of one ingest run, 0 disables" default:"5m"`
+		// RateLimit and RateBurst token bucket of the requests to one feed host, shared by all sources.
+		RateLimit float64 `yml:"rate_limit" env:"RATE_LIMIT" long:"rate-limit" description:"Max requests per second to one feed host, 0 disables" default:"2"`
+		RateBurst int     `yml:"rate_burst" env:"RATE_BURST" long:"rate-burst" description:"Burst of requests to one feed host" default:"2"`
+		// Proxy, UserAgent, CloudflareBypass and RequestTimeout tune the client of the feed requests.
+		Proxy            string        `yml:"proxy" env:"PROXY" long:"proxy" description:"Proxy url of the feed requests, HTTP_PROXY envs are used when empty"`
+		UserAgent        string        `yml:"user_agent" env:"USER_AGENT" long:"user-agent" description:"User-Agent of the feed requests, random browser one when empty"`
+		CloudflareBypass int8          `yml:"cloudflare_bypass" env:"CLOUDFLARE_BYPASS" long:"cloudflare-bypass" description:"Enable Cloudflare bypass of the feed requests" default:"1"`
+		RequestTimeout   time.Duration `yml:"request_timeout" env:"REQUEST_TIMEOUT" long:"request-timeout" description:"Timeout of one feed request attempt" default:"20s"`
+		// ListRetry and ArticleRetry retry policies of the feed list and article details requests.
+		ListRetry    Retry `yml:"list_retry" env-namespace:"LIST_RETRY" namespace:"list-retry" group:"Feed list retry options"`
+		ArticleRetry Retry `yml:"article_retry" env-namespace:"ARTICLE_RETRY" namespace:"article-retry" group:"Article details retry options"`
+		// SourcesFile YAML file with the list of sources, see Sources.
+		SourcesFile string `yml:"sources_file" env:"SOURCES_FILE" long:"sources-file" description:"YAML file with feed sources, overrides team/url/count/job-time"`
+		// Sources feeds to parse, when empty one source is made from Team, URL, Count and JobTime.
+		Sources []Source `yaml:"sources" no-flag:"true"`
+	}
+	// Source one club feed, empty Count and JobTime are taken from Parser,
+	// empty Provider means incrowd.
+	// The sources file is decoded by cleanenv with gopkg.in/yaml, it reads only yaml tags,
+	// yml tags of the options above aren't read by any decoder and would break the file.
+	Source struct {
+		Team     string        `yaml:"team"`
+		Provider string        `yaml:"provider"`
+		URL      string        `yaml:"url"`
+		Count    int           `yaml:"count"`
+		JobTime  time.Duration `yaml:"time"`
+	}
+	// Retry policy of a feed endpoint, delays grow exponentially from BaseDelay to MaxDelay.
+	Retry struct {
+		Attempts   int           `yml:"attempts" env:"ATTEMPTS" long:"attempts" description:"Max attempts of a request" default:"4"`
+		BaseDelay  time.Duration `yml:"base_delay" env:"BASE_DELAY" long:"base-delay" description:"Delay before the first retry" default:"500ms"`
+		MaxDelay   time.Duration `yml:"max_delay" env:"MAX_DELAY" long:"max-delay" description:"Max delay between retries" default:"10s"`
+		MaxElapsed time.Duration `yml:"max_elapsed" env:"MAX_ELAPSED" long:"max-elapsed" description:"No retries after this time since the first attempt" default:"20s"`
 	}
 	Http struct {
 		Port         int           `yml:"port" env:"PORT" long:"port" description:"" default:"8080"`
@@ -34,6 +116,13 @@ type (
 		WriteTimeout time.Duration `yml:"write_timeout" env:"WRITE_TIMEOUT" long:"write_timeout" description:"Write timeout" default:"100s"`
 		ReadTimeout  time.Duration `yml:"read_timeout" env:"READ_TIMEOUT" long:"read_timeout" description:"Read timeout" default:"100s"`
 		IdleTimeout  time.Duration `yml:"idle_timeout" env:"IDLE_TIMEOUT" long:"idle_timeout" description:"Idle timeout" default:"100s"`
+		// HandlerTimeout and SearchTimeout max time of the handlers, the request gets 503 after it.
+		HandlerTimeout time.Duration `yml:"handler_timeout" env:"HANDLER_TIMEOUT" long:"handler_timeout" description:"Handler timeout" default:"10s"`
+		SearchTimeout  time.Duration `yml:"search_timeout" env:"SEARCH_TIMEOUT" long:"search_timeout" description:"Search handler timeout" default:"30s"`
+		// AdminToken bearer token of /v1/admin endpoints, they are not served when it's empty.
+		AdminToken string `yml:"admin_token" env:"ADMIN_TOKEN" long:"admin_token" description:"Bearer token of the admin endpoints, they are off when empty"`
+		// ReadyIngestAge max age of the last successful ingest of every source for /readyz, 0 disables the check.
+		ReadyIngestAge time.Duration `yml:"ready_ingest_age" env:"READY_INGEST_AGE" long:"ready_ingest_age" description:"Not ready when the last successful ingest is older, 0 disables" default:"0"`
 	}
 	Logger struct {
 		Level string `env:"LEVEL" long:"level" description:"Log level to use; environment-base level is used when empty" `
@@ -43,8 +132,17 @@ type (
 // MustLoad reads flags and envs and returns Config
 // that corresponds to the values read.
 func MustLoad() *Config {
+	config, _ := MustLoadWithArgs()
+
+	return config
+}
+
+// MustLoadWithArgs reads flags and envs and returns Config
+// with the remaining positional arguments.
+func MustLoadWithArgs() (*Config, []string) {
 	var config Config
-	if _, err := flags.Parse(&config); err != nil {
+	args, err := flags.Parse(&config)
+	if err != nil {
 		var flagsErr *flags.Error
 		if errors.As(err, &flagsErr) && flagsErr.Type == flags.ErrHelp {
 			panic("help")
@@ -52,7 +150,51 @@ func MustLoad() *Config {
 		panic("failed to parse config")
 	}
 
-	return &config
+	if config.Parser.SourcesFile != "" {
+		var f struct {
+			Sources []Source `yaml:"sources"`
+		}
+		if err := cleanenv.ReadConfig(config.Parser.SourcesFile, &f); err != nil {
+			panic("cannot read sources: " + err.Error())
+		}
+		config.Parser.Sources = f.Sources
+	}
+
+	return &config, args
+}
+
+// FeedSources return sources to parse with defaults applied.
+func (p Parser) FeedSources() ([]Source, error) {
+	sources := p.Sources
+	if len(sources) == 0 {
+		team := p.Team
+		if team == "" {
+			team = entity.DefaultTeamId
+		}
+		sources = []Source{{Team: team, Provider: p.Provider, URL: p.URL}}
+	}
+
+	res := make([]Source, 0, len(sources))
+	teams := make(map[string]struct{}, len(sources))
+	for _, s := range sources {
+		if s.Team == "" || s.URL == "" {
+			return nil, fmt.Errorf("source %q: team and url are required", s.Team)
+		}
+		if _, ok := teams[s.Team]; ok {
+			return nil, fmt.Errorf("source %q: duplicate team", s.Team)
+		}
+		teams[s.Team] = struct{}{}
+
+		if s.Count <= 0 {
+			s.Count = p.Count
+		}
+		if s.JobTime <= 0 {
+			s.JobTime = p.JobTime
+		}
+		res = append(res, s)
+	}
+
+	return res, nil
 }
 
 // MustLoadFromYAML read cfg from YAML file
@@ -0,0 +1,439 @@
+// Package content sanitizes club CMS html of the articles
+// and makes plain text and block renditions of it.
+package content
+
+import (
+	"bytes"
+	"go.sport-news/internal/entity"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Result renditions of the article body.
+type Result struct {
+	HTML   string
+	Text   string
+	Blocks []entity.Block
+}
+
+// allowed tags with their allowed attributes, other tags are unwrapped to their content.
+//
+//nolint:gochecknoglobals
+var allowed = map[atom.Atom][]string{
+	atom.P: nil, atom.Br: nil, atom.Hr: nil, atom.Blockquote: nil, atom.Pre: nil, atom.Code: nil,
+	atom.Strong: nil, atom.B: nil, atom.Em: nil, atom.I: nil, atom.U: nil, atom.S: nil, atom.Sub: nil, atom.Sup: nil,
+	atom.H1: nil, atom.H2: nil, atom.H3: nil, atom.H4: nil, atom.H5: nil, atom.H6: nil,
+	atom.Ul: nil, atom.Ol: nil, atom.Li: nil, atom.Figure: nil, atom.Figcaption: nil,
+	atom.Table: nil, atom.Thead: nil, atom.Tbody: nil, atom.Tr: nil,
+	atom.Th:     {"colspan", "rowspan"},
+	atom.Td:     {"colspan", "rowspan"},
+	atom.A:      {"href", "title"},
+	atom.Img:    {"src", "alt", "title", "width", "height"},
+	atom.Iframe: {"src", "title", "width", "height", "allowfullscreen"},
+}
+
+// dropped tags are removed together with their content.
+//
+//nolint:gochecknoglobals
+var dropped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Head: true, atom.Title: true, atom.Meta: true, atom.Link: true, atom.Base: true,
+	atom.Object: true, atom.Embed: true, atom.Applet: true, atom.Svg: true, atom.Math: true,
+	atom.Canvas: true, atom.Audio: true, atom.Video: true,
+	atom.Form: true, atom.Input: true, atom.Button: true, atom.Select: true, atom.Textarea: true,
+}
+
+// embedHosts hosts whose iframes are kept, subdomains included.
+//
+//nolint:gochecknoglobals
+var embedHosts = []string{
+	"youtube.com", "youtube-nocookie.com", "vimeo.com", "dailymotion.com",
+	"twitter.com", "instagram.com", "facebook.com", "soundcloud.com", "spotify.com",
+}
+
+//nolint:gochecknoglobals
+var inline = map[atom.Atom]bool{
+	atom.A: true, atom.Br: true, atom.Code: true,
+	atom.Strong: true, atom.B: true, atom.Em: true, atom.I: true, atom.U: true, atom.S: true, atom.Sub: true, atom.Sup: true,
+}
+
+// Render sanitize raw html to the allow-list, relative urls are resolved against base
+// and dropped when base is empty.
+func Render(raw, base string) Result {
+	b, err := url.Parse(strings.TrimSpace(base))
+	if err != nil || !b.IsAbs() {
+		b = nil
+	}
+
+	root := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(raw), root)
+	if err != nil {
+		return Result{}
+	}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+	sanitize(root, b)
+
+	var buf bytes.Buffer
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		_ = html.Render(&buf, c)
+	}
+
+	bb := &blocks{}
+	bb.walk(root)
+	bb.flush()
+
+	texts := make([]string, 0, len(bb.res))
+	for _, bl := range bb.res {
+		if bl.Text != "" {
+			texts = append(texts, bl.Text)
+		}
+	}
+
+	return Result{
+		HTML:   strings.TrimSpace(buf.String()),
+		Text:   strings.Join(texts, "\n\n"),
+		Blocks: bb.res,
+	}
+}
+
+// Apply render the article content in place,
+// relative urls are resolved against the club website or the article url.
+func Apply(a *entity.Article) {
+	base := a.ClubWebsiteURL
+	if base == "" {
+		base = a.URL
+	}
+
+	r := Render(a.Content, base)
+	a.Content, a.ContentText, a.Blocks = r.HTML, r.Text, r.Blocks
+}
+
+type action int
+
+const (
+	keep action = iota
+	unwrap
+	drop
+)
+
+func sanitize(n *html.Node, base *url.URL) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+
+		switch {
+		case c.Type == html.TextNode:
+		case c.Type != html.ElementNode, dropped[c.DataAtom]:
+			n.RemoveChild(c)
+		default:
+			attrs, ok := allowed[c.DataAtom]
+			act := unwrap
+			if ok {
+				act = clean(c, attrs, base)
+			}
+
+			switch act {
+			case drop:
+				n.RemoveChild(c)
+			case unwrap:
+				sanitize(c, base)
+				for gc := c.FirstChild; gc != nil; gc = c.FirstChild {
+					c.RemoveChild(gc)
+					n.InsertBefore(gc, c)
+				}
+				n.RemoveChild(c)
+			case keep:
+				sanitize(c, base)
+			}
+		}
+
+		c = next
+	}
+}
+
+// clean keep allowed attributes of the element and resolve its urls.
+func clean(n *html.Node, attrs []string, base *url.URL) action {
+	if n.DataAtom == atom.Img && hidden(n) {
+		return drop
+	}
+
+	kept := make([]html.Attribute, 0, len(n.Attr))
+	for _, a := range n.Attr {
+		if a.Namespace != "" || !contains(attrs, a.Key) {
+			continue
+		}
+		if a.Key == "href" || a.Key == "src" {
+			u, ok := resolve(a.Val, base, a.Key == "href")
+			if !ok {
+				continue
+			}
+			a.Val = u
+		}
+		kept = append(kept, a)
+	}
+	n.Attr = kept
+
+	switch n.DataAtom {
+	case atom.A:
+		if attr(n, "href") == "" {
+			return unwrap
+		}
+		n.Attr = append(n.Attr, html.Attribute{Key: "rel", Val: "noopener noreferrer"})
+	case atom.Img:
+		if attr(n, "src") == "" {
+			return drop
+		}
+	case atom.Iframe:
+		if src := attr(n, "src"); src == "" || !embeddable(src) {
+			return drop
+		}
+	}
+
+	return keep
+}
+
+// hidden report tracking pixels, tiny or invisible images.
+func hidden(n *html.Node) bool {
+	for _, k := range []string{"width", "height"} {
+		if v, err := strconv.Atoi(strings.TrimSuffix(attr(n, k), "px")); err == nil && v <= 1 {
+			return true
+		}
+	}
+
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+// resolve make absolute http url, mailto is allowed for links.
+func resolve(v string, base *url.URL, link bool) (string, bool) {
+	v = strings.TrimSpace(v)
+	if strings.HasPrefix(v, "//") {
+		v = "https:" + v
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || v == "" {
+		return "", false
+	}
+	if !u.IsAbs() {
+		if base == nil {
+			return "", false
+		}
+		u = base.ResolveReference(u)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.String(), true
+	case "mailto":
+		return u.String(), link
+	default:
+		return "", false
+	}
+}
+
+func embeddable(src string) bool {
+	u, err := url.Parse(src)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, h := range embedHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// blocks builder, inline nodes are collected to a paragraph
+// until the next block element.
+type blocks struct {
+	res []entity.Block
+	run []*html.Node
+}
+
+func (b *blocks) walk(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode, inline[c.DataAtom] && !hasMedia(c):
+			b.run = append(b.run, c)
+		case c.DataAtom == atom.Img, c.DataAtom == atom.Iframe:
+			b.flush()
+			b.media(c, "")
+		case c.DataAtom == atom.Figure && hasMedia(c):
+			b.flush()
+			b.figure(c)
+		case heading(c) > 0:
+			b.flush()
+			if t := text(c); t != "" {
+				b.res = append(b.res, entity.Block{Type: entity.BlockHeading, Text: t, Level: heading(c)})
+			}
+		default:
+			b.flush()
+			b.walk(c)
+			b.flush()
+		}
+	}
+}
+
+// flush make a paragraph of the collected inline nodes.
+func (b *blocks) flush() {
+	defer func() { b.run = b.run[:0] }()
+
+	var (
+		t   strings.Builder
+		buf bytes.Buffer
+	)
+	for _, n := range b.run {
+		t.WriteString(rawText(n))
+		_ = html.Render(&buf, n)
+	}
+
+	if s := normalize(t.String()); s != "" {
+		b.res = append(b.res, entity.Block{
+			Type: entity.BlockParagraph,
+			Text: s,
+			HTML: strings.TrimSpace(buf.String()),
+		})
+	}
+}
+
+func (b *blocks) figure(n *html.Node) {
+	var (
+		m       *html.Node
+		caption string
+	)
+	find(n, func(c *html.Node) bool {
+		switch {
+		case m == nil && (c.DataAtom == atom.Img || c.DataAtom == atom.Iframe):
+			m = c
+		case c.DataAtom == atom.Figcaption:
+			caption = text(c)
+		}
+
+		return c.DataAtom != atom.Figcaption
+	})
+
+	// media only inside the caption, the figure is a plain container
+	if m == nil {
+		b.walk(n)
+		b.flush()
+		return
+	}
+
+	b.media(m, caption)
+}
+
+func (b *blocks) media(n *html.Node, caption string) {
+	typ, kind := entity.BlockImage, entity.MediaImage
+	if n.DataAtom == atom.Iframe {
+		typ, kind = entity.BlockEmbed, entity.MediaEmbed
+	} else if caption == "" {
+		caption = strings.TrimSpace(attr(n, "alt"))
+	}
+
+	m := entity.NewMedia(attr(n, "src"), kind)
+	if caption != "" {
+		m.Caption = &caption
+	}
+	if w, err := strconv.Atoi(attr(n, "width")); err == nil && w > 0 {
+		m.Width = &w
+	}
+	if h, err := strconv.Atoi(attr(n, "height")); err == nil && h > 0 {
+		m.Height = &h
+	}
+
+	b.res = append(b.res, entity.Block{Type: typ, Media: &m})
+}
+
+// heading return level of h1-h6 element, 0 for others.
+func heading(n *html.Node) int {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return int(n.Data[1] - '0')
+	default:
+		return 0
+	}
+}
+
+func hasMedia(n *html.Node) bool {
+	var found bool
+	find(n, func(c *html.Node) bool {
+		found = found || c.DataAtom == atom.Img || c.DataAtom == atom.Iframe
+
+		return !found
+	})
+
+	return found
+}
+
+// find call fn for every descendant element, fn returns false to skip the children.
+func find(n *html.Node, fn func(*html.Node) bool) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && fn(c) {
+			find(c, fn)
+		}
+	}
+}
+
+func text(n *html.Node) string {
+	return normalize(rawText(n))
+}
+
+// rawText text of the node, br is a line break.
+func rawText(n *html.Node) string {
+	switch {
+	case n.Type == html.TextNode:
+		return n.Data
+	case n.DataAtom == atom.Br:
+		return "\n"
+	}
+
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(rawText(c))
+	}
+
+	return sb.String()
+}
+
+// normalize collapse spaces inside lines and drop empty lines.
+func normalize(s string) string {
+	lines := strings.Split(s, "\n")
+	res := lines[:0]
+	for _, l := range lines {
+		if l = strings.Join(strings.Fields(l), " "); l != "" {
+			res = append(res, l)
+		}
+	}
+
+	return strings.Join(res, "\n")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+
+	return false
+}
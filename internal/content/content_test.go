@@ -0,0 +1,112 @@
+package content
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.sport-news/internal/entity"
+	"testing"
+)
+
+func TestRender_HTML(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		base string
+		want string
+	}{
+		{
+			name: "scripts and handlers",
+			raw:  `<p onclick="x()">Hi<script>alert(1)</script><style>p{}</style></p>`,
+			want: `<p>Hi</p>`,
+		},
+		{
+			name: "unknown tags are unwrapped",
+			raw:  `<div class="a"><span style="color:red">Hi</span> <font>there</font></div>`,
+			want: `Hi there`,
+		},
+		{
+			name: "relative urls",
+			raw:  `<a href="/news/2">more</a><img src="i.jpg">`,
+			base: "https://club.test/news/",
+			want: `<a href="https://club.test/news/2" rel="noopener noreferrer">more</a><img src="https://club.test/news/i.jpg"/>`,
+		},
+		{
+			name: "relative urls without base",
+			raw:  `<a href="/news/2">more</a><img src="i.jpg">`,
+			want: `more`,
+		},
+		{
+			name: "unsafe links",
+			raw:  `<a href="javascript:alert(1)">x</a><a href="mailto:a@club.test">y</a><img src="data:image/png;base64,AA==">`,
+			want: `x<a href="mailto:a@club.test" rel="noopener noreferrer">y</a>`,
+		},
+		{
+			name: "tracking pixels",
+			raw:  `<img src="https://t.test/p.gif" width="1" height="1"><img src="https://t.test/q.gif" style="display: none">`,
+			want: ``,
+		},
+		{
+			name: "embeds",
+			raw:  `<iframe src="//www.youtube.com/embed/abc"></iframe><iframe src="https://ads.test/x"></iframe>`,
+			want: `<iframe src="https://www.youtube.com/embed/abc"></iframe>`,
+		},
+		{
+			name: "comments",
+			raw:  `<p>Hi<!-- cms --></p>`,
+			want: `<p>Hi</p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.raw, tt.base)
+			assert.Equal(t, tt.want, got.HTML)
+			assert.Equal(t, got.HTML, Render(got.HTML, tt.base).HTML, "render is idempotent")
+		})
+	}
+}
+
+func TestRender_Blocks(t *testing.T) {
+	r := Render(
+		`<h2>Match <b>report</b></h2>`+
+			`<p>First   line<br>second <a href="/p">line</a></p>`+
+			`<p><img src="/1.jpg" alt="Goal" width="640"></p>`+
+			`<figure><iframe src="https://player.vimeo.com/video/1"></iframe><figcaption>Highlights</figcaption></figure>`+
+			`<ul><li>One</li><li>Two</li></ul>`,
+		"https://club.test",
+	)
+
+	caption, alt, width := "Highlights", "Goal", 640
+	assert.Equal(t, []entity.Block{
+		{Type: entity.BlockHeading, Text: "Match report", Level: 2},
+		{
+			Type: entity.BlockParagraph,
+			Text: "First line\nsecond line",
+			HTML: `First   line<br/>second <a href="https://club.test/p" rel="noopener noreferrer">line</a>`,
+		},
+		{Type: entity.BlockImage, Media: &entity.Media{URL: "https://club.test/1.jpg", Kind: entity.MediaImage, Caption: &alt, Width: &width}},
+		{Type: entity.BlockEmbed, Media: &entity.Media{URL: "https://player.vimeo.com/video/1", Kind: entity.MediaVimeo, Caption: &caption}},
+		{Type: entity.BlockParagraph, Text: "One", HTML: "One"},
+		{Type: entity.BlockParagraph, Text: "Two", HTML: "Two"},
+	}, r.Blocks)
+	assert.Equal(t, "Match report\n\nFirst line\nsecond line\n\nOne\n\nTwo", r.Text)
+}
+
+func TestRender_BlocksFigureCaptionMedia(t *testing.T) {
+	r := Render(`<figure><figcaption>cap <img src="https://x.com/a.jpg"></figcaption></figure>`, "")
+
+	assert.Equal(t, []entity.Block{
+		{Type: entity.BlockParagraph, Text: "cap", HTML: "cap"},
+		{Type: entity.BlockImage, Media: &entity.Media{URL: "https://x.com/a.jpg", Kind: entity.MediaImage}},
+	}, r.Blocks)
+}
+
+func TestApply(t *testing.T) {
+	a := entity.Article{Content: `<p><a href="/x">x</a></p>`, URL: "https://club.test/news/1"}
+	Apply(&a)
+	assert.Equal(t, `<p><a href="https://club.test/x" rel="noopener noreferrer">x</a></p>`, a.Content)
+	assert.Equal(t, "x", a.ContentText)
+	assert.Len(t, a.Blocks, 1)
+
+	a = entity.Article{Content: `<a href="/x">x</a>`, URL: "https://club.test/news/1", ClubWebsiteURL: "https://www.club.test"}
+	Apply(&a)
+	assert.Equal(t, `<a href="https://www.club.test/x" rel="noopener noreferrer">x</a>`, a.Content)
+}
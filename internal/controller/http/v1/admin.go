@@ -0,0 +1,221 @@
+package v1
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.sport-news/internal/entity"
+	"go.sport-news/internal/repository"
+	"go.sport-news/internal/scheduler"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AdminController struct {
+	responder
+	ingestRepository repository.IngestRepository
+	jobs             scheduler.Manager
+	token            string
+}
+
+type IAdminController interface {
+	Auth(next http.Handler) http.Handler
+	GetIngestRuns(w http.ResponseWriter, r *http.Request)
+	GetIngestRun(w http.ResponseWriter, r *http.Request)
+	GetJobs(w http.ResponseWriter, r *http.Request)
+	GetJob(w http.ResponseWriter, r *http.Request)
+	RunJob(w http.ResponseWriter, r *http.Request)
+	PauseJob(w http.ResponseWriter, r *http.Request)
+	ResumeJob(w http.ResponseWriter, r *http.Request)
+}
+
+// NewAdminController return admin controller, nil jobs means the parser is off.
+func NewAdminController(
+	ingestRepository repository.IngestRepository,
+	jobs scheduler.Manager,
+	token string,
+	logger *zap.Logger,
+) *AdminController {
+	return &AdminController{
+		responder:        responder{logger: logger},
+		ingestRepository: ingestRepository,
+		jobs:             jobs,
+		token:            token,
+	}
+}
+
+// Auth middleware of the admin endpoints, requests need Authorization: Bearer <token>.
+func (c *AdminController) Auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || c.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.token)) != 1 {
+			c.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetIngestRuns handle GET /v1/admin/ingest/runs?team=&status=&limit= - the latest runs first.
+func (c *AdminController) GetIngestRuns(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := repository.RunFilter{Team: q.Get("team"), Status: q.Get("status")}
+
+	switch filter.Status {
+	case "", entity.IngestRunning, entity.IngestSuccess, entity.IngestFailed, entity.IngestNotModified:
+	default:
+		c.badRequestResponse(w, "status is not valid")
+		return
+	}
+
+	limit := repository.DefaultPageLimit
+	if v := q.Get("limit"); v != "" {
+		l, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || l < 1 || l > repository.MaxPageLimit {
+			c.badRequestResponse(w, fmt.Sprintf("limit must be between 1 and %d", repository.MaxPageLimit))
+			return
+		}
+		limit = l
+	}
+
+	runs, err := c.ingestRepository.GetRuns(r.Context(), filter, limit)
+	if err != nil {
+		c.log(r).Error("failed get ingest runs", zap.String("url", r.URL.String()), zap.Error(err))
+		c.internalErrorResponse(w)
+		return
+	}
+
+	ti, l := len(runs), int(limit)
+	s := "-startedAt"
+	c.respondWithJSON(w, responseCache{
+		status: http.StatusOK,
+		response: response{
+			Status: success,
+			Data:   runs,
+			Metadata: meta{
+				CreatedAt:  time.Now().Format(timeFormat),
+				TotalItems: &ti,
+				Sort:       &s,
+				Limit:      &l,
+			},
+		},
+	})
+}
+
+// GetIngestRun handle GET /v1/admin/ingest/runs/{id}.
+func (c *AdminController) GetIngestRun(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	run, err := c.ingestRepository.GetRun(r.Context(), id)
+	if err == repository.ErrRunNotFound { //nolint:errorlint
+		c.errorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		c.log(r).Error("failed get ingest run", zap.String("url", r.URL.String()), zap.Error(err))
+		c.internalErrorResponse(w)
+		return
+	}
+
+	c.respondWithJSON(w, responseCache{
+		status: http.StatusOK,
+		response: response{
+			Status: success,
+			Data:   run,
+			Metadata: meta{
+				CreatedAt: time.Now().Format(timeFormat),
+			},
+		},
+	})
+}
+
+// GetJobs handle GET /v1/admin/jobs - jobs of all sources with their next and last run.
+func (c *AdminController) GetJobs(w http.ResponseWriter, r *http.Request) {
+	if c.jobs == nil {
+		c.errorResponse(w, http.StatusNotFound, "parser is off")
+		return
+	}
+
+	jobs := c.jobs.Jobs()
+	ti := len(jobs)
+	c.respondWithJSON(w, responseCache{
+		status: http.StatusOK,
+		response: response{
+			Status: success,
+			Data:   jobs,
+			Metadata: meta{
+				CreatedAt:  time.Now().Format(timeFormat),
+				TotalItems: &ti,
+			},
+		},
+	})
+}
+
+// GetJob handle GET /v1/admin/jobs/{team}.
+func (c *AdminController) GetJob(w http.ResponseWriter, r *http.Request) {
+	c.jobAction(w, r, http.StatusOK, nil)
+}
+
+// RunJob handle POST /v1/admin/jobs/{team}/run - ingest the source at once, the run goes in background.
+func (c *AdminController) RunJob(w http.ResponseWriter, r *http.Request) {
+	c.jobAction(w, r, http.StatusAccepted, func(team string) error { return c.jobs.RunNow(team) })
+}
+
+// PauseJob handle POST /v1/admin/jobs/{team}/pause - scheduled runs are skipped until resume.
+func (c *AdminController) PauseJob(w http.ResponseWriter, r *http.Request) {
+	c.jobAction(w, r, http.StatusOK, func(team string) error { return c.jobs.Pause(team) })
+}
+
+// ResumeJob handle POST /v1/admin/jobs/{team}/resume.
+func (c *AdminController) ResumeJob(w http.ResponseWriter, r *http.Request) {
+	c.jobAction(w, r, http.StatusOK, func(team string) error { return c.jobs.Resume(team) })
+}
+
+// jobAction do the action with the team job and respond with the job state.
+func (c *AdminController) jobAction(w http.ResponseWriter, r *http.Request, status int, action func(team string) error) {
+	if c.jobs == nil {
+		c.errorResponse(w, http.StatusNotFound, "parser is off")
+		return
+	}
+
+	team := mux.Vars(r)["team"]
+	var err error
+	if action != nil {
+		err = action(team)
+	}
+
+	var job scheduler.JobState
+	if err == nil {
+		job, err = c.jobs.Job(team)
+	}
+
+	//nolint:errorlint
+	switch err {
+	case nil:
+	case scheduler.ErrJobNotFound:
+		c.errorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case scheduler.ErrJobPaused, scheduler.ErrJobRunning:
+		c.errorResponse(w, http.StatusConflict, err.Error())
+		return
+	default:
+		c.log(r).Error("failed job action", zap.String("url", r.URL.String()), zap.Error(err))
+		c.internalErrorResponse(w)
+		return
+	}
+
+	c.respondWithJSON(w, responseCache{
+		status: status,
+		response: response{
+			Status: success,
+			Data:   job,
+			Metadata: meta{
+				CreatedAt: time.Now().Format(timeFormat),
+			},
+		},
+	})
+}
@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.sport-news/internal/entity"
+	"go.sport-news/internal/repository"
+	"go.sport-news/internal/repository/mocks"
+	"go.sport-news/internal/scheduler"
+	sm "go.sport-news/internal/scheduler/mocks"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminController_Auth(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		header   string
+		wantCode int
+	}{
+		{name: "valid token", token: "secret", header: "Bearer secret", wantCode: http.StatusOK},
+		{name: "wrong token", token: "secret", header: "Bearer nope", wantCode: http.StatusUnauthorized},
+		{name: "no bearer", token: "secret", header: "secret", wantCode: http.StatusUnauthorized},
+		{name: "no token configured", header: "Bearer ", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewAdminController(nil, nil, tt.token, zap.NewNop())
+			h := c.Auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/admin/ingest/runs", nil)
+			r.Header.Set("Authorization", tt.header)
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestAdminController_GetIngestRuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		filter   repository.RunFilter
+		limit    int64
+		wantCode int
+	}{
+		{name: "defaults", limit: repository.DefaultPageLimit, wantCode: http.StatusOK},
+		{
+			name:     "filters",
+			query:    "team=t94&status=failed&limit=5",
+			filter:   repository.RunFilter{Team: "t94", Status: entity.IngestFailed},
+			limit:    5,
+			wantCode: http.StatusOK,
+		},
+		{name: "bad status", query: "status=broken", wantCode: http.StatusBadRequest},
+		{name: "bad limit", query: "limit=1000", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := mocks.NewIngestRepository(t)
+			if tt.wantCode == http.StatusOK {
+				rep.On("GetRuns", mock.Anything, tt.filter, tt.limit).Return([]entity.IngestRun{{ID: "1"}}, nil)
+			}
+			c := NewAdminController(rep, nil, "secret", zap.NewNop())
+
+			w := httptest.NewRecorder()
+			c.GetIngestRuns(w, httptest.NewRequest(http.MethodGet, "/v1/admin/ingest/runs?"+tt.query, nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestAdminController_GetIngestRun(t *testing.T) {
+	rep := mocks.NewIngestRepository(t)
+	rep.On("GetRun", mock.Anything, "1").Return(&entity.IngestRun{ID: "1"}, nil)
+	rep.On("GetRun", mock.Anything, "2").Return(nil, repository.ErrRunNotFound)
+	c := NewAdminController(rep, nil, "secret", zap.NewNop())
+
+	for id, want := range map[string]int{"1": http.StatusOK, "2": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v1/admin/ingest/runs/"+id, nil), map[string]string{"id": id})
+		c.GetIngestRun(w, r)
+
+		assert.Equal(t, want, w.Code)
+	}
+}
+
+func TestAdminController_Jobs(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  func(c *AdminController) http.HandlerFunc
+		team     string
+		setup    func(m *sm.Manager)
+		wantCode int
+	}{
+		{
+			name:    "get",
+			handler: func(c *AdminController) http.HandlerFunc { return c.GetJob },
+			team:    "t94",
+			setup: func(m *sm.Manager) {
+				m.On("Job", "t94").Return(scheduler.JobState{Team: "t94"}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:    "run",
+			handler: func(c *AdminController) http.HandlerFunc { return c.RunJob },
+			team:    "t94",
+			setup: func(m *sm.Manager) {
+				m.On("RunNow", "t94").Return(nil)
+				m.On("Job", "t94").Return(scheduler.JobState{Team: "t94"}, nil)
+			},
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:    "run paused",
+			handler: func(c *AdminController) http.HandlerFunc { return c.RunJob },
+			team:    "t94",
+			setup: func(m *sm.Manager) {
+				m.On("RunNow", "t94").Return(scheduler.ErrJobPaused)
+			},
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:    "run running",
+			handler: func(c *AdminController) http.HandlerFunc { return c.RunJob },
+			team:    "t94",
+			setup: func(m *sm.Manager) {
+				m.On("RunNow", "t94").Return(scheduler.ErrJobRunning)
+			},
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:    "pause unknown",
+			handler: func(c *AdminController) http.HandlerFunc { return c.PauseJob },
+			team:    "t1",
+			setup: func(m *sm.Manager) {
+				m.On("Pause", "t1").Return(scheduler.ErrJobNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:    "resume",
+			handler: func(c *AdminController) http.HandlerFunc { return c.ResumeJob },
+			team:    "t94",
+			setup: func(m *sm.Manager) {
+				m.On("Resume", "t94").Return(nil)
+				m.On("Job", "t94").Return(scheduler.JobState{Team: "t94"}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := sm.NewManager(t)
+			tt.setup(m)
+			c := NewAdminController(nil, m, "secret", zap.NewNop())
+
+			w := httptest.NewRecorder()
+			r := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/v1/admin/jobs/"+tt.team, nil), map[string]string{"team": tt.team})
+			tt.handler(c)(w, r)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestAdminController_JobsParserOff(t *testing.T) {
+	c := NewAdminController(nil, nil, "secret", zap.NewNop())
+
+	w := httptest.NewRecorder()
+	c.GetJobs(w, httptest.NewRequest(http.MethodGet, "/v1/admin/jobs", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
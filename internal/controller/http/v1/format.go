@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"go.sport-news/internal/entity"
+	"net/http"
+)
+
+// Formats of the article content, chosen by the format query param.
+const (
+	formatHTML   = "html"
+	formatText   = "text"
+	formatBlocks = "blocks"
+)
+
+// article it's an article with the content in the requested format.
+type article struct {
+	entity.Article
+	Content string         `json:"content,omitempty"`
+	Blocks  []entity.Block `json:"blocks,omitempty"`
+}
+
+// render article content in the format, blocks replace the content.
+func render(a entity.Article, format string) article {
+	switch format {
+	case formatText:
+		return article{Article: a, Content: a.ContentText}
+	case formatBlocks:
+		return article{Article: a, Blocks: a.Blocks}
+	default:
+		return article{Article: a, Content: a.Content}
+	}
+}
+
+func renderAll(articles []entity.Article, format string) []article {
+	res := make([]article, 0, len(articles))
+	for _, a := range articles {
+		res = append(res, render(a, format))
+	}
+
+	return res
+}
+
+// parseFormat read format query param, html by default,
+// responds with bad request when it's not valid.
+func (c *NewsController) parseFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	switch v := r.URL.Query().Get("format"); v {
+	case "":
+		return formatHTML, true
+	case formatHTML, formatText, formatBlocks:
+		return v, true
+	default:
+		c.badRequestResponse(w, "format must be html, text or blocks")
+		return "", false
+	}
+}
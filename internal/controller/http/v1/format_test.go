@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	a := entity.Article{
+		ID:          "1",
+		Content:     "<p>Hi</p>",
+		ContentText: "Hi",
+		Blocks:      []entity.Block{{Type: entity.BlockParagraph, Text: "Hi", HTML: "Hi"}},
+	}
+
+	tests := []struct {
+		format      string
+		wantContent any
+		wantBlocks  bool
+	}{
+		{format: formatHTML, wantContent: "<p>Hi</p>"},
+		{format: formatText, wantContent: "Hi"},
+		{format: formatBlocks, wantContent: nil, wantBlocks: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			b, err := json.Marshal(render(a, tt.format))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var got map[string]any
+			assert.NoError(t, json.Unmarshal(b, &got))
+			assert.Equal(t, tt.wantContent, got["content"])
+			_, ok := got["blocks"]
+			assert.Equal(t, tt.wantBlocks, ok)
+			assert.NotContains(t, got, "contentText")
+		})
+	}
+}
+
+func TestNewsController_parseFormat(t *testing.T) {
+	c := NewNewsController(nil, nil, config.CacheTTL{}, zap.NewNop())
+
+	for query, want := range map[string]string{"": formatHTML, "format=text": formatText, "format=blocks": formatBlocks} {
+		w := httptest.NewRecorder()
+		format, ok := c.parseFormat(w, httptest.NewRequest(http.MethodGet, "/v1/teams/t94/news?"+query, nil))
+		assert.True(t, ok)
+		assert.Equal(t, want, format)
+	}
+
+	w := httptest.NewRecorder()
+	_, ok := c.parseFormat(w, httptest.NewRequest(http.MethodGet, "/v1/teams/t94/news?format=xml", nil))
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
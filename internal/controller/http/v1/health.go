@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/repository"
+	"go.sport-news/internal/tracing"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// checkTimeout of one readiness check.
+const checkTimeout = 2 * time.Second
+
+// Statuses of the health check.
+const (
+	checkOK   = "ok"
+	checkFail = "fail"
+)
+
+type HealthController struct {
+	responder
+	db               database.DB
+	ingestRepository repository.IngestRepository
+	teams            []string
+	maxIngestAge     time.Duration
+}
+
+type IHealthController interface {
+	Live(w http.ResponseWriter, r *http.Request)
+	Ready(w http.ResponseWriter, r *http.Request)
+}
+
+// check result of one readiness check.
+type check struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// NewHealthController return health controller, the ingest of the teams is checked
+// when maxIngestAge is more than 0.
+func NewHealthController(
+	db database.DB,
+	ingestRepository repository.IngestRepository,
+	teams []string,
+	maxIngestAge time.Duration,
+	logger *zap.Logger,
+) *HealthController {
+	return &HealthController{
+		responder:        responder{logger: logger},
+		db:               db,
+		ingestRepository: ingestRepository,
+		teams:            teams,
+		maxIngestAge:     maxIngestAge,
+	}
+}
+
+// Live handle GET /healthz - the process is alive.
+func (c *HealthController) Live(w http.ResponseWriter, _ *http.Request) {
+	c.respondWithJSON(w, responseCache{
+		status: http.StatusOK,
+		response: response{
+			Status: success,
+			Metadata: meta{
+				CreatedAt: time.Now().Format(timeFormat),
+			},
+		},
+	})
+}
+
+// Ready handle GET /readyz - mongo is reachable and the ingest is fresh.
+func (c *HealthController) Ready(w http.ResponseWriter, r *http.Request) {
+	checks := []check{c.run(r.Context(), "mongo", c.db.Ping)}
+	if c.maxIngestAge > 0 {
+		for _, team := range c.teams {
+			checks = append(checks, c.run(r.Context(), "ingest:"+team, func(ctx context.Context) error {
+				return c.ingestFresh(ctx, team)
+			}))
+		}
+	}
+
+	resp := response{
+		Status: success,
+		Data:   checks,
+		Metadata: meta{
+			CreatedAt: time.Now().Format(timeFormat),
+		},
+	}
+	code := http.StatusOK
+	for _, ch := range checks {
+		if ch.Status != checkOK {
+			resp.Status, resp.Message = errors, "not ready"
+			code = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.respondWithJSON(w, responseCache{status: code, response: resp})
+}
+
+func (c *HealthController) run(ctx context.Context, name string, fn func(ctx context.Context) error) check {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := fn(ctx)
+	ch := check{
+		Name:      name,
+		Status:    checkOK,
+		LatencyMs: float64(time.Since(started).Microseconds()) / 1000,
+	}
+	if err != nil {
+		tracing.Logger(ctx, c.logger).Warn("readiness check failed", zap.String("check", name), zap.Error(err))
+		ch.Status, ch.Error = checkFail, err.Error()
+	}
+
+	return ch
+}
+
+// ingestFresh fail when the last successful ingest of the team is older than maxIngestAge.
+func (c *HealthController) ingestFresh(ctx context.Context, team string) error {
+	run, err := c.ingestRepository.LastSuccessfulRun(ctx, team)
+	if err == repository.ErrRunNotFound { //nolint:errorlint
+		return fmt.Errorf("no successful ingest")
+	}
+	if err != nil {
+		return err
+	}
+
+	if age := time.Since(run.StartedAt); age > c.maxIngestAge {
+		return fmt.Errorf("last successful ingest %s ago", age.Truncate(time.Second))
+	}
+
+	return nil
+}
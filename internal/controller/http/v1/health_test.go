@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	dm "go.sport-news/internal/database/mocks"
+	"go.sport-news/internal/entity"
+	"go.sport-news/internal/repository"
+	"go.sport-news/internal/repository/mocks"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthController_Live(t *testing.T) {
+	c := NewHealthController(nil, nil, nil, 0, zap.NewNop())
+
+	w := httptest.NewRecorder()
+	c.Live(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHealthController_Ready(t *testing.T) {
+	tests := []struct {
+		name       string
+		ping       error
+		maxAge     time.Duration
+		run        *entity.IngestRun
+		runErr     error
+		wantCode   int
+		wantChecks map[string]string
+	}{
+		{
+			name:       "mongo only",
+			wantCode:   http.StatusOK,
+			wantChecks: map[string]string{"mongo": checkOK},
+		},
+		{
+			name:       "mongo down",
+			ping:       fmt.Errorf("timeout"),
+			wantCode:   http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"mongo": checkFail},
+		},
+		{
+			name:       "fresh ingest",
+			maxAge:     time.Hour,
+			run:        &entity.IngestRun{StartedAt: time.Now().Add(-time.Minute)},
+			wantCode:   http.StatusOK,
+			wantChecks: map[string]string{"mongo": checkOK, "ingest:t94": checkOK},
+		},
+		{
+			name:       "stale ingest",
+			maxAge:     time.Hour,
+			run:        &entity.IngestRun{StartedAt: time.Now().Add(-2 * time.Hour)},
+			wantCode:   http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"mongo": checkOK, "ingest:t94": checkFail},
+		},
+		{
+			name:       "no ingest",
+			maxAge:     time.Hour,
+			runErr:     repository.ErrRunNotFound,
+			wantCode:   http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"mongo": checkOK, "ingest:t94": checkFail},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := dm.NewDB(t)
+			db.On("Ping", mock.Anything).Return(tt.ping)
+			rep := mocks.NewIngestRepository(t)
+			if tt.maxAge > 0 {
+				rep.On("LastSuccessfulRun", mock.Anything, "t94").Return(tt.run, tt.runErr)
+			}
+			c := NewHealthController(db, rep, []string{"t94"}, tt.maxAge, zap.NewNop())
+
+			w := httptest.NewRecorder()
+			c.Ready(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			var resp struct {
+				Data []check `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			got := make(map[string]string, len(resp.Data))
+			for _, ch := range resp.Data {
+				got[ch.Name] = ch.Status
+			}
+			assert.Equal(t, tt.wantChecks, got)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	snippetRadius = 60
+	maxSnippets   = 3
+)
+
+var tagRe = regexp.MustCompile(`<[^>]*>`)
+
+// searchTerms split query into terms to highlight,
+// negated terms are skipped like mongo text search does.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, t := range strings.Fields(query) {
+		if strings.HasPrefix(t, "-") {
+			continue
+		}
+		t = strings.TrimFunc(t, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if t != "" {
+			terms = append(terms, strings.Map(unicode.ToLower, t))
+		}
+	}
+
+	return terms
+}
+
+// highlight return html escaped snippets of texts around the terms,
+// terms are wrapped with <em>. Texts can be html, tags are dropped.
+func highlight(terms []string, texts ...string) []string {
+	var snippets []string
+	for _, text := range texts {
+		text = strings.Join(strings.Fields(html.UnescapeString(tagRe.ReplaceAllString(text, " "))), " ")
+		runes := []rune(text)
+		// rune by rune lower case keeps indexes of runes and lower the same
+		lower := make([]rune, len(runes))
+		for i, r := range runes {
+			lower[i] = unicode.ToLower(r)
+		}
+
+		end := 0
+		for i := 0; i < len(lower) && len(snippets) < maxSnippets; i++ {
+			if i < end || termAt(lower, i, terms) == 0 {
+				continue
+			}
+
+			from := max(0, i-snippetRadius)
+			end = min(len(runes), i+snippetRadius)
+			snippets = append(snippets, mark(runes, lower, from, end, terms))
+		}
+	}
+
+	return snippets
+}
+
+// termAt return length of the term starting at i on a word boundary, 0 if none.
+func termAt(text []rune, i int, terms []string) int {
+	if i > 0 && (unicode.IsLetter(text[i-1]) || unicode.IsNumber(text[i-1])) {
+		return 0
+	}
+	for _, t := range terms {
+		tr := []rune(t)
+		if i+len(tr) <= len(text) && string(text[i:i+len(tr)]) == t {
+			return len(tr)
+		}
+	}
+
+	return 0
+}
+
+// mark escape snippet runes[from:to] and wrap terms with <em>.
+func mark(runes, lower []rune, from, to int, terms []string) string {
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	for i := from; i < to; {
+		if n := termAt(lower, i, terms); n > 0 {
+			n = min(n, to-i)
+			b.WriteString("<em>" + html.EscapeString(string(runes[i:i+n])) + "</em>")
+			i += n
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+		i++
+	}
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
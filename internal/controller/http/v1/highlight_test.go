@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_searchTerms(t *testing.T) {
+	assert.Equal(t, []string{"leeds", "united"}, searchTerms(`"Leeds United" -Town`))
+	assert.Empty(t, searchTerms("  -only "))
+}
+
+func Test_highlight(t *testing.T) {
+	terms := searchTerms("train leeds")
+
+	got := highlight(terms, "NO TRAIN SERVICES", "<p>Essential information for fans ahead of the <b>Leeds</b> United game &amp; more.</p>")
+	assert.Equal(t, []string{
+		"NO <em>TRAIN</em> SERVICES",
+		"Essential information for fans ahead of the <em>Leeds</em> United game &amp; more.",
+	}, got)
+
+	// terms inside words are not matched
+	assert.Empty(t, highlight(terms, "constraint"))
+
+	long := strings.Repeat("word ", 40) + "train " + strings.Repeat("word ", 40)
+	got = highlight(terms, long)
+	if assert.Len(t, got, 1) {
+		assert.True(t, strings.HasPrefix(got[0], "…"))
+		assert.True(t, strings.HasSuffix(got[0], "…"))
+		assert.Contains(t, got[0], "<em>train</em>")
+	}
+}
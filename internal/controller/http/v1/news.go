@@ -4,10 +4,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/mux"
-	"github.com/patrickmn/go-cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.sport-news/internal/cache"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/entity"
+	"go.sport-news/internal/metrics"
 	"go.sport-news/internal/repository"
 	"go.uber.org/zap"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,6 +27,11 @@ type response struct {
 	Message  string      `json:"message,omitempty"`
 	Metadata meta        `json:"metadata,omitempty"`
 }
+type searchItem struct {
+	article
+	Score      float64  `json:"score"`
+	Highlights []string `json:"highlights,omitempty"`
+}
 type responseCache struct {
 	status   int
 	response response
@@ -27,6 +42,10 @@ type meta struct {
 	CreatedAt  string  `json:"createdAt"`
 	TotalItems *int    `json:"totalItems,omitempty"`
 	Sort       *string `json:"sort,omitempty"`
+	Limit      *int    `json:"limit,omitempty"`
+	Page       *int    `json:"page,omitempty"`
+	NextCursor *string `json:"nextCursor,omitempty"`
+	PrevCursor *string `json:"prevCursor,omitempty"`
 }
 
 const (
@@ -35,25 +54,95 @@ const (
 )
 const timeFormat string = "2006-01-02T15:04:05Z"
 
+var optaMatchIDRe = regexp.MustCompile(`^g?\d+$`)
+
 type NewsController struct {
+	responder
 	newsRepository repository.NewsRepository
-	cache          *cache.Cache
-	logger         *zap.Logger
+	cache          cache.Cache
+	ttl            config.CacheTTL
 }
 
 type INewsController interface {
 	GetTeamNews(w http.ResponseWriter, r *http.Request)
 	GetTeamNewsByID(w http.ResponseWriter, r *http.Request)
+	SearchTeamNews(w http.ResponseWriter, r *http.Request)
+	GetTeamCategories(w http.ResponseWriter, r *http.Request)
 	ResetCache(w http.ResponseWriter, r *http.Request)
 }
 
-func NewNewsController(newsRepository repository.NewsRepository, cache *cache.Cache, logger *zap.Logger) *NewsController {
-	return &NewsController{newsRepository: newsRepository, cache: cache, logger: logger}
+// cachedResponse encoded response kept in the cache.
+type cachedResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+func NewNewsController(
+	newsRepository repository.NewsRepository,
+	newsCache cache.Cache,
+	ttl config.CacheTTL,
+	logger *zap.Logger,
+) *NewsController {
+	return &NewsController{responder: responder{logger: logger}, newsRepository: newsRepository, cache: newsCache, ttl: ttl}
 }
 
-// ResetCache handle GET /v1/cache-flush - reset cache.
+// cached respond with the cached response of the key, the lookup is counted and added to the request span.
+// A failed lookup is a miss.
+func (c *NewsController) cached(w http.ResponseWriter, r *http.Request, key string) bool {
+	v, found, err := c.cache.Get(r.Context(), key)
+	if err != nil {
+		c.log(r).Warn("failed get cached response", zap.String("key", key), zap.Error(err))
+	}
+
+	var cr cachedResponse
+	if found {
+		if err = json.Unmarshal(v, &cr); err != nil {
+			c.log(r).Warn("failed decode cached response", zap.String("key", key), zap.Error(err))
+			found = false
+		}
+	}
+
+	metrics.CacheLookup("news", found)
+	if m, ok := c.cache.(interface{ Len() int }); ok {
+		metrics.CacheItems("news", m.Len())
+	}
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.Bool("cache.hit", found))
+
+	if found {
+		c.write(w, cr.Status, cr.Body)
+	}
+
+	return found
+}
+
+// store cache the response by key for ttl and respond with it,
+// the response is sent even when the cache fails.
+func (c *NewsController) store(w http.ResponseWriter, r *http.Request, key string, ttl time.Duration, resp responseCache) {
+	body, err := json.Marshal(resp.response)
+	if err != nil {
+		c.log(r).Error("failed encode response", zap.Error(err))
+		c.internalErrorResponse(w)
+		return
+	}
+
+	v, err := json.Marshal(cachedResponse{Status: resp.status, Body: body})
+	if err == nil {
+		err = c.cache.Set(r.Context(), key, v, ttl)
+	}
+	if err != nil {
+		c.log(r).Warn("failed cache response", zap.String("key", key), zap.Error(err))
+	}
+
+	c.write(w, resp.status, body)
+}
+
+// ResetCache handle POST /v1/cache-flush - reset cache, the redis one is reset for all replicas.
 func (c *NewsController) ResetCache(w http.ResponseWriter, r *http.Request) {
-	c.cache.Flush()
+	if err := c.cache.Flush(r.Context()); err != nil {
+		c.log(r).Error("failed flush cache", zap.Error(err))
+		c.internalErrorResponse(w)
+		return
+	}
 
 	c.respondWithJSON(w, responseCache{
 		status: http.StatusOK,
@@ -66,126 +155,423 @@ func (c *NewsController) ResetCache(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetTeamNews handle GET /v1/teams/{team}/news.
+// GetTeamNews handle GET /v1/teams/{team}/news?limit=&cursor=&type=&optaMatchId=&from=&to=&hasVideo=&hasGallery=&format=.
 func (c *NewsController) GetTeamNews(w http.ResponseWriter, r *http.Request) {
-	resp, found := c.cache.Get(r.URL.String())
-	if !found {
-		vars := mux.Vars(r)
-		team := vars["team"]
+	vars := mux.Vars(r)
+	team := vars["team"]
+
+	page, ok := c.parsePage(w, r)
+	if !ok {
+		return
+	}
+	filter, ok := c.parseNewsFilter(w, r)
+	if !ok {
+		return
+	}
+	format, ok := c.parseFormat(w, r)
+	if !ok {
+		return
+	}
+
+	// equal queries in any order share one cache entry
+	q := listQuery(filter, page)
+	q.Set("team", team)
+	q.Set("format", format)
+	key := cache.Key("news", q)
+	if c.cached(w, r, key) {
+		return
+	}
+
+	if ok := c.validateExist(w, r, key, c.ttl.List, "teamId", team); !ok {
+		return
+	}
+
+	p, err := c.newsRepository.GetTeamNews(r.Context(), team, filter, page)
+	if err != nil {
+		c.log(r).Error("failed get getTeamNews", zap.String("url", r.URL.String()), zap.Error(err))
+		c.internalErrorResponse(w)
+		return
+	}
+	ti := int(p.TotalItems)
+	l := int(page.Size())
+	s := "-published"
+
+	m := meta{
+		CreatedAt:  time.Now().Format(timeFormat),
+		TotalItems: &ti,
+		Sort:       &s,
+		Limit:      &l,
+	}
+	if p.Next != nil {
+		next := p.Next.Encode()
+		m.NextCursor = &next
+	}
+	if p.Prev != nil {
+		prev := p.Prev.Encode()
+		m.PrevCursor = &prev
+	}
 
-		if ok := c.validateExist(w, r, "teamId", team); !ok {
+	c.store(w, r, key, c.ttl.List, responseCache{
+		status: http.StatusOK,
+		response: response{
+			Status:   success,
+			Data:     renderAll(p.Articles, format),
+			Metadata: m,
+		},
+	})
+}
+
+// SearchTeamNews handle GET /v1/teams/{team}/news/search?q=&page=&limit=&format=.
+func (c *NewsController) SearchTeamNews(w http.ResponseWriter, r *http.Request) {
+	format, ok := c.parseFormat(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	team := vars["team"]
+	q := r.URL.Query()
+
+	query := q.Get("q")
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		c.badRequestResponse(w, "q is required")
+		return
+	}
+
+	page, limit := int64(1), repository.DefaultPageLimit
+	if v := q.Get("page"); v != "" {
+		p, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || p < 1 {
+			c.badRequestResponse(w, "page must be a positive number")
 			return
 		}
-
-		a, err := c.newsRepository.GetTeamNews(r.Context(), team)
-		if err != nil {
-			c.logger.Error("failed get getTeamNews", zap.String("url", r.URL.String()))
-			c.internalErrorResponse(w)
+		page = p
+	}
+	if v := q.Get("limit"); v != "" {
+		l, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || l < 1 || l > repository.MaxPageLimit {
+			c.badRequestResponse(w, fmt.Sprintf("limit must be between 1 and %d", repository.MaxPageLimit))
 			return
 		}
-		ti := len(*a)
-		s := "-published"
+		limit = l
+	}
 
-		resp = responseCache{
-			status: http.StatusOK,
+	key := cache.Key("search", url.Values{
+		"team":   {team},
+		"q":      {query},
+		"page":   {strconv.FormatInt(page, 10)},
+		"limit":  {strconv.FormatInt(limit, 10)},
+		"format": {format},
+	})
+	if c.cached(w, r, key) {
+		return
+	}
+
+	if ok := c.validateExist(w, r, key, c.ttl.Search, "teamId", team); !ok {
+		return
+	}
+
+	res, err := c.newsRepository.SearchTeamNews(r.Context(), team, query, page, limit)
+	if err != nil {
+		c.log(r).Error("failed search team news", zap.String("url", r.URL.String()), zap.Error(err))
+		c.internalErrorResponse(w)
+		return
+	}
+
+	items := make([]searchItem, 0, len(res.Hits))
+	for _, h := range res.Hits {
+		items = append(items, searchItem{
+			article:    render(h.Article, format),
+			Score:      h.Score,
+			Highlights: highlight(terms, h.Title, h.Teaser, h.ContentText),
+		})
+	}
+
+	ti, p, l := int(res.TotalItems), int(page), int(limit)
+	s := "-score"
+	c.store(w, r, key, c.ttl.Search, responseCache{
+		status: http.StatusOK,
+		response: response{
+			Status: success,
+			Data:   items,
+			Metadata: meta{
+				CreatedAt:  time.Now().Format(timeFormat),
+				TotalItems: &ti,
+				Sort:       &s,
+				Limit:      &l,
+				Page:       &p,
+			},
+		},
+	})
+}
+
+// GetTeamCategories handle GET /v1/teams/{team}/categories.
+func (c *NewsController) GetTeamCategories(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	team := vars["team"]
+
+	key := cache.Key("categories", url.Values{"team": {team}})
+	if c.cached(w, r, key) {
+		return
+	}
+
+	if ok := c.validateExist(w, r, key, c.ttl.Categories, "teamId", team); !ok {
+		return
+	}
+
+	categories, err := c.newsRepository.GetTeamCategories(r.Context(), team)
+	if err != nil {
+		c.log(r).Error("failed get team categories", zap.String("url", r.URL.String()), zap.Error(err))
+		c.internalErrorResponse(w)
+		return
+	}
+	ti := len(categories)
+	s := "-count"
+
+	c.store(w, r, key, c.ttl.Categories, responseCache{
+		status: http.StatusOK,
+		response: response{
+			Status: success,
+			Data:   categories,
+			Metadata: meta{
+				CreatedAt:  time.Now().Format(timeFormat),
+				TotalItems: &ti,
+				Sort:       &s,
+			},
+		},
+	})
+}
+
+// GetTeamNewsByID handle GET /v1/teams/{team}/news/{id}?format=.
+func (c *NewsController) GetTeamNewsByID(w http.ResponseWriter, r *http.Request) {
+	format, ok := c.parseFormat(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	team := vars["team"]
+	id := vars["id"]
+
+	key := cache.Key("article", url.Values{"team": {team}, "id": {id}, "format": {format}})
+	if c.cached(w, r, key) {
+		return
+	}
+
+	if ok := c.validateExist(w, r, key, c.ttl.Article, "teamId", team); !ok {
+		return
+	}
+	if ok := c.validateExist(w, r, key, c.ttl.Article, "id", id); !ok {
+		return
+	}
+
+	a, err := c.newsRepository.GetTeamNewsByID(r.Context(), team, id)
+	if err == repository.ErrArticleNotFound { //nolint:errorlint
+		c.log(r).Error("not found GetTeamNewsByID",
+			zap.String("url", r.URL.String()),
+			zap.String("team", team),
+			zap.String("uuid", id),
+		)
+		c.store(w, r, key, c.ttl.Article, responseCache{
+			status: http.StatusNotFound,
 			response: response{
-				Status: success,
+				Status: errors,
 				Data:   &a,
 				Metadata: meta{
-					CreatedAt:  time.Now().Format(timeFormat),
-					TotalItems: &ti,
-					Sort:       &s,
+					CreatedAt: time.Now().Format(timeFormat),
 				},
 			},
-		}
-		c.cache.Set(r.URL.String(), resp, cache.DefaultExpiration)
+		})
+		return
+	}
+	if err != nil {
+		c.log(r).Error("failed GetTeamNewsByID",
+			zap.String("url", r.URL.String()),
+			zap.String("team", team),
+			zap.String("uuid", id),
+			zap.Error(err),
+		)
+		c.internalErrorResponse(w)
+		return
 	}
 
-	c.respondWithJSON(w, resp.(responseCache))
+	c.store(w, r, key, c.ttl.Article, responseCache{
+		status: http.StatusOK,
+		response: response{
+			Status: success,
+			Data:   render(*a, format),
+			Metadata: meta{
+				CreatedAt: time.Now().Format(timeFormat),
+			},
+		},
+	})
 }
 
-// GetTeamNewsByID handle GET /v1/teams/{team}/news/{id}.
-func (c *NewsController) GetTeamNewsByID(w http.ResponseWriter, r *http.Request) {
-	resp, found := c.cache.Get(r.URL.String())
-	if !found {
-		vars := mux.Vars(r)
-		team := vars["team"]
-		id := vars["id"]
+// parsePage read limit and cursor query params,
+// responds with bad request when they are not valid.
+func (c *NewsController) parsePage(w http.ResponseWriter, r *http.Request) (repository.Page, bool) {
+	var (
+		page repository.Page
+		q    = r.URL.Query()
+	)
 
-		if ok := c.validateExist(w, r, "teamId", team); !ok {
-			return
-		}
-		if ok := c.validateExist(w, r, "id", id); !ok {
-			return
+	if v := q.Get("limit"); v != "" {
+		l, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || l < 1 || l > repository.MaxPageLimit {
+			c.badRequestResponse(w, fmt.Sprintf("limit must be between 1 and %d", repository.MaxPageLimit))
+			return page, false
 		}
+		page.Limit = l
+	}
 
-		a, err := c.newsRepository.GetTeamNewsByID(r.Context(), team, id)
+	if v := q.Get("cursor"); v != "" {
+		cur, err := repository.DecodeCursor(v)
 		if err != nil {
-			c.logger.Error("not found GetTeamNewsByID",
-				zap.String("url", r.URL.String()),
-				zap.String("team", team),
-				zap.String("uuid", id),
-			)
-			resp = responseCache{
-				status: http.StatusNotFound,
-				response: response{
-					Status: errors,
-					Data:   &a,
-					Metadata: meta{
-						CreatedAt: time.Now().Format(timeFormat),
-					},
-				},
+			c.badRequestResponse(w, err.Error())
+			return page, false
+		}
+		page.Cursor = cur
+	}
+
+	return page, true
+}
+
+// parseNewsFilter read list filters from query params,
+// responds with bad request when they are not valid.
+func (c *NewsController) parseNewsFilter(w http.ResponseWriter, r *http.Request) (repository.NewsFilter, bool) {
+	var (
+		f repository.NewsFilter
+		q = r.URL.Query()
+	)
+
+	// type=a&type=b and type=a,b are the same, names and slugs too
+	types := make(map[string]struct{})
+	for _, v := range q["type"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = entity.Slug(t); t != "" {
+				types[t] = struct{}{}
 			}
-			c.cache.Set(r.URL.String(), resp, cache.DefaultExpiration)
-			c.respondWithJSON(w, resp.(responseCache))
-			return
 		}
+	}
+	for t := range types {
+		f.Types = append(f.Types, t)
+	}
+	sort.Strings(f.Types)
 
-		resp = responseCache{
-			status: http.StatusOK,
-			response: response{
-				Status: success,
-				Data:   &a,
-				Metadata: meta{
-					CreatedAt: time.Now().Format(timeFormat),
-				},
-			},
+	if v := q.Get("optaMatchId"); v != "" {
+		if !optaMatchIDRe.MatchString(v) {
+			c.badRequestResponse(w, "optaMatchId is not valid")
+			return f, false
+		}
+		f.OptaMatchID = v
+	}
+
+	for _, p := range []struct {
+		name     string
+		dst      **time.Time
+		endOfDay bool
+	}{{"from", &f.From, false}, {"to", &f.To, true}} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
 		}
-		c.cache.Set(r.URL.String(), resp, cache.DefaultExpiration)
+		t, err := parseTime(v, p.endOfDay)
+		if err != nil {
+			c.badRequestResponse(w, fmt.Sprintf("%s must be RFC 3339 time or YYYY-MM-DD date", p.name))
+			return f, false
+		}
+		*p.dst = &t
+	}
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		c.badRequestResponse(w, "from must be before to")
+		return f, false
+	}
 
+	for _, p := range []struct {
+		name string
+		dst  **bool
+	}{{"hasVideo", &f.HasVideo}, {"hasGallery", &f.HasGallery}} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			c.badRequestResponse(w, fmt.Sprintf("%s must be true or false", p.name))
+			return f, false
+		}
+		*p.dst = &b
 	}
 
-	c.respondWithJSON(w, resp.(responseCache))
+	return f, true
 }
 
-func (c *NewsController) respondWithJSON(w http.ResponseWriter, resp responseCache) {
-	response, err := json.Marshal(resp.response)
-	if err != nil {
-		c.internalErrorResponse(w)
+// parseTime parse RFC 3339 time or a date,
+// with endOfDay the date is the last millisecond of the day.
+func parseTime(v string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(resp.status)
-	_, err = w.Write(response)
+	t, err := time.Parse(time.DateOnly, v)
 	if err != nil {
-		c.logger.Error("failed send json response", zap.Error(err))
+		return t, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Millisecond)
 	}
+
+	return t, nil
 }
 
-func (c *NewsController) internalErrorResponse(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-	_, err := w.Write([]byte(fmt.Sprintf("{\"status\":\"%s\",\"message\":\"%s\"}", errors, "internal server error")))
-	if err != nil {
-		c.logger.Error("failed send internal error response", zap.Error(err))
+// listQuery canonical query of the news list, used as a cache key.
+func listQuery(f repository.NewsFilter, page repository.Page) url.Values {
+	v := url.Values{}
+	v.Set("limit", strconv.FormatInt(page.Size(), 10))
+	if page.Cursor != nil {
+		v.Set("cursor", page.Cursor.Encode())
+	}
+	if len(f.Types) > 0 {
+		v.Set("type", strings.Join(f.Types, ","))
+	}
+	if f.OptaMatchID != "" {
+		v.Set("optaMatchId", f.OptaMatchID)
+	}
+	if f.From != nil {
+		v.Set("from", f.From.Format(time.RFC3339Nano))
 	}
+	if f.To != nil {
+		v.Set("to", f.To.Format(time.RFC3339Nano))
+	}
+	if f.HasVideo != nil {
+		v.Set("hasVideo", strconv.FormatBool(*f.HasVideo))
+	}
+	if f.HasGallery != nil {
+		v.Set("hasGallery", strconv.FormatBool(*f.HasGallery))
+	}
+
+	return v
 }
 
-// validateExist check exist field with value ond db.
-func (c *NewsController) validateExist(w http.ResponseWriter, r *http.Request, field string, value any) bool {
+// validateExist check exist field with value ond db,
+// not found response is cached by key for ttl, a failed check isn't cached.
+func (c *NewsController) validateExist(
+	w http.ResponseWriter,
+	r *http.Request,
+	key string,
+	ttl time.Duration,
+	field string,
+	value any,
+) bool {
 	exist, err := c.newsRepository.Exist(r.Context(), field, value)
-	if err != nil || !exist {
-		resp := responseCache{
+	if err != nil {
+		c.log(r).Error("failed check exist", zap.String("field", field), zap.Error(err))
+		c.internalErrorResponse(w)
+		return false
+	}
+	if !exist {
+		c.store(w, r, key, ttl, responseCache{
 			status: http.StatusNotFound,
 			response: response{
 				Status:  errors,
@@ -194,10 +580,7 @@ func (c *NewsController) validateExist(w http.ResponseWriter, r *http.Request, f
 					CreatedAt: time.Now().Format(timeFormat),
 				},
 			},
-		}
-
-		c.cache.Set(r.URL.String(), resp, cache.DefaultExpiration)
-		c.respondWithJSON(w, resp)
+		})
 		return false
 	}
 	return true
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.sport-news/internal/cache"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/repository"
+	"go.sport-news/internal/repository/mocks"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewsController_parseNewsFilter(t *testing.T) {
+	c := NewNewsController(nil, nil, config.CacheTTL{}, zap.NewNop())
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantKey  string
+	}{
+		{
+			name:     "canonical key ignores order, repeats and unknown params",
+			query:    "hasVideo=1&type=Match+Report&utm=x&type=club-news,Match+Report&optaMatchId=g2370928",
+			wantCode: http.StatusOK,
+			wantKey:  "hasVideo=true&limit=50&optaMatchId=g2370928&type=club-news%2Cmatch-report",
+		},
+		{
+			name:     "date range",
+			query:    "to=2024-02-28&from=2024-02-01T10:00:00%2B01:00",
+			wantCode: http.StatusOK,
+			wantKey:  "from=2024-02-01T09%3A00%3A00Z&limit=50&to=2024-02-28T23%3A59%3A59.999Z",
+		},
+		{name: "bad date", query: "from=yesterday", wantCode: http.StatusBadRequest},
+		{name: "from after to", query: "from=2024-03-01&to=2024-02-01", wantCode: http.StatusBadRequest},
+		{name: "bad match", query: "optaMatchId=g23x", wantCode: http.StatusBadRequest},
+		{name: "bad flag", query: "hasGallery=maybe", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/teams/t94/news?"+tt.query, nil)
+
+			f, ok := c.parseNewsFilter(w, r)
+			assert.Equal(t, tt.wantCode, w.Code)
+			if ok {
+				page, _ := c.parsePage(w, r)
+				assert.Equal(t, tt.wantKey, listQuery(f, page).Encode())
+			}
+		})
+	}
+}
+
+func TestNewsController_cache(t *testing.T) {
+	rep := mocks.NewNewsRepository(t)
+	c := NewNewsController(rep, cache.NewMemory(time.Minute), config.CacheTTL{Search: time.Minute}, zap.NewNop())
+
+	search := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v1/teams/t94/news/search?"+query, nil)
+		c.SearchTeamNews(w, mux.SetURLVars(r, map[string]string{"team": "t94"}))
+		return w
+	}
+
+	rep.On("Exist", mock.Anything, "teamId", "t94").Return(true, nil).Twice()
+	rep.On("SearchTeamNews", mock.Anything, "t94", "derby", int64(1), int64(10)).
+		Return(&repository.SearchResult{TotalItems: 0}, nil).Twice()
+
+	first := search("q=derby&limit=10")
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	// permuted and default params share the entry
+	for _, q := range []string{"limit=10&q=derby", "page=1&format=html&q=derby&limit=10"} {
+		w := search(q)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, first.Body.String(), w.Body.String())
+	}
+
+	w := httptest.NewRecorder()
+	c.ResetCache(w, httptest.NewRequest(http.MethodPost, "/v1/cache-flush", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the flushed cache goes to the repository again
+	assert.Equal(t, http.StatusOK, search("q=derby&limit=10").Code)
+}
+
+func TestNewsController_validateExist(t *testing.T) {
+	rep := mocks.NewNewsRepository(t)
+	c := NewNewsController(rep, cache.NewMemory(time.Minute), config.CacheTTL{Categories: time.Minute}, zap.NewNop())
+
+	categories := func() int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v1/teams/t1/categories", nil)
+		c.GetTeamCategories(w, mux.SetURLVars(r, map[string]string{"team": "t1"}))
+		return w.Code
+	}
+
+	// a failed check isn't cached as not found
+	rep.On("Exist", mock.Anything, "teamId", "t1").Return(false, fmt.Errorf("timeout")).Once()
+	assert.Equal(t, http.StatusInternalServerError, categories())
+
+	// not found is cached
+	rep.On("Exist", mock.Anything, "teamId", "t1").Return(false, nil).Once()
+	assert.Equal(t, http.StatusNotFound, categories())
+	assert.Equal(t, http.StatusNotFound, categories())
+}
+
+func TestNewsController_GetTeamNewsByID(t *testing.T) {
+	rep := mocks.NewNewsRepository(t)
+	c := NewNewsController(rep, cache.NewMemory(time.Minute), config.CacheTTL{Article: time.Minute}, zap.NewNop())
+
+	article := func() int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v1/teams/t1/news/a1", nil)
+		c.GetTeamNewsByID(w, mux.SetURLVars(r, map[string]string{"team": "t1", "id": "a1"}))
+		return w.Code
+	}
+
+	rep.On("Exist", mock.Anything, "teamId", "t1").Return(true, nil)
+	rep.On("Exist", mock.Anything, "id", "a1").Return(true, nil)
+
+	// a failed read isn't cached as not found
+	rep.On("GetTeamNewsByID", mock.Anything, "t1", "a1").Return(nil, fmt.Errorf("timeout")).Once()
+	assert.Equal(t, http.StatusInternalServerError, article())
+
+	// not found is cached
+	rep.On("GetTeamNewsByID", mock.Anything, "t1", "a1").Return(nil, repository.ErrArticleNotFound).Once()
+	assert.Equal(t, http.StatusNotFound, article())
+	assert.Equal(t, http.StatusNotFound, article())
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.sport-news/internal/requestid"
+	"go.sport-news/internal/tracing"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// responder writes jsend responses.
+type responder struct {
+	logger *zap.Logger
+}
+
+// log return logger with request id and trace ids of the request.
+func (c responder) log(r *http.Request) *zap.Logger {
+	l := tracing.Logger(r.Context(), c.logger)
+	if id := requestid.IDFromCtx(r.Context()); id != "" {
+		l = l.With(zap.String("requestId", id))
+	}
+
+	return l
+}
+
+func (c responder) respondWithJSON(w http.ResponseWriter, resp responseCache) {
+	response, err := json.Marshal(resp.response)
+	if err != nil {
+		c.internalErrorResponse(w)
+		return
+	}
+
+	c.write(w, resp.status, response)
+}
+
+// write encoded json body.
+func (c responder) write(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		c.logger.Error("failed send json response", zap.Error(err))
+	}
+}
+
+func (c responder) internalErrorResponse(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	_, err := w.Write([]byte(fmt.Sprintf("{\"status\":\"%s\",\"message\":\"%s\"}", errors, "internal server error")))
+	if err != nil {
+		c.logger.Error("failed send internal error response", zap.Error(err))
+	}
+}
+
+func (c responder) badRequestResponse(w http.ResponseWriter, message string) {
+	c.errorResponse(w, http.StatusBadRequest, message)
+}
+
+func (c responder) errorResponse(w http.ResponseWriter, status int, message string) {
+	c.respondWithJSON(w, responseCache{
+		status: status,
+		response: response{
+			Status:  errors,
+			Message: message,
+			Metadata: meta{
+				CreatedAt: time.Now().Format(timeFormat),
+			},
+		},
+	})
+}
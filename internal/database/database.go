@@ -5,8 +5,17 @@ import "context"
 //go:generate go run github.com/vektra/mockery/v2@v2.42.0 --name DB
 type DB interface {
 	Disconnect(ctx context.Context)
+	Ping(ctx context.Context) error
+	EnsureIndexes(ctx context.Context) error
+	DropIndex(ctx context.Context, name string) error
+	WithCollection(name string) DB
 	InsertMany(ctx context.Context, documents []interface{}) error
+	BulkUpsert(ctx context.Context, filters []interface{}, updates []interface{}) (int64, error)
 	Find(ctx context.Context, filter interface{}, opts interface{}, dataType interface{}) (interface{}, error)
 	FindOne(ctx context.Context, filter interface{}, opts interface{}, dataType interface{}) (interface{}, error)
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts interface{}) (int64, error)
+	UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts interface{}) (int64, error)
+	Aggregate(ctx context.Context, pipeline interface{}, opts interface{}, dataType interface{}) (interface{}, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts interface{}) (int64, error)
 	DeleteMany(ctx context.Context, filter interface{}, opts interface{}) (int64, error)
 }
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.sport-news/internal/metrics"
+	"go.sport-news/internal/tracing"
+	"time"
+)
+
+// Instrumented db which observes latency of every operation and traces it.
+type Instrumented struct {
+	db         DB
+	collection string
+}
+
+// Instrument wrap db, collection is the name of its collection in the metrics and spans.
+func Instrument(db DB, collection string) *Instrumented {
+	return &Instrumented{db: db, collection: collection}
+}
+
+// start span of the operation, returned done ends it and observes the operation.
+func (i *Instrumented) start(ctx context.Context, operation string) (context.Context, func(err error)) {
+	started := time.Now()
+	ctx, span := tracing.Start(
+		ctx,
+		"mongo."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.mongodb.collection", i.collection),
+			attribute.String("db.operation", operation),
+		),
+	)
+
+	return ctx, func(err error) {
+		result := "ok"
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			result, err = "not_found", nil
+		case err != nil:
+			result = "error"
+		}
+		span.SetAttributes(attribute.String("db.result", result))
+
+		metrics.MongoOperation(i.collection, operation, result, time.Since(started))
+		tracing.End(span, err)
+	}
+}
+
+func (i *Instrumented) Disconnect(ctx context.Context) {
+	i.db.Disconnect(ctx)
+}
+
+func (i *Instrumented) Ping(ctx context.Context) (err error) {
+	ctx, done := i.start(ctx, "ping")
+	defer func() { done(err) }()
+
+	return i.db.Ping(ctx)
+}
+
+func (i *Instrumented) EnsureIndexes(ctx context.Context) (err error) {
+	ctx, done := i.start(ctx, "ensure_indexes")
+	defer func() { done(err) }()
+
+	return i.db.EnsureIndexes(ctx)
+}
+
+func (i *Instrumented) DropIndex(ctx context.Context, name string) (err error) {
+	ctx, done := i.start(ctx, "drop_index")
+	defer func() { done(err) }()
+
+	return i.db.DropIndex(ctx, name)
+}
+
+func (i *Instrumented) WithCollection(name string) DB {
+	return Instrument(i.db.WithCollection(name), name)
+}
+
+func (i *Instrumented) InsertMany(ctx context.Context, documents []interface{}) (err error) {
+	ctx, done := i.start(ctx, "insert_many")
+	defer func() { done(err) }()
+
+	return i.db.InsertMany(ctx, documents)
+}
+
+func (i *Instrumented) BulkUpsert(ctx context.Context, filters []interface{}, updates []interface{}) (n int64, err error) {
+	ctx, done := i.start(ctx, "bulk_upsert")
+	defer func() { done(err) }()
+
+	return i.db.BulkUpsert(ctx, filters, updates)
+}
+
+func (i *Instrumented) Find(
+	ctx context.Context,
+	filter interface{},
+	opts interface{},
+	dataType interface{},
+) (res interface{}, err error) {
+	ctx, done := i.start(ctx, "find")
+	defer func() { done(err) }()
+
+	return i.db.Find(ctx, filter, opts, dataType)
+}
+
+func (i *Instrumented) FindOne(
+	ctx context.Context,
+	filter interface{},
+	opts interface{},
+	dataType interface{},
+) (res interface{}, err error) {
+	ctx, done := i.start(ctx, "find_one")
+	defer func() { done(err) }()
+
+	return i.db.FindOne(ctx, filter, opts, dataType)
+}
+
+func (i *Instrumented) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts interface{}) (n int64, err error) {
+	ctx, done := i.start(ctx, "update_one")
+	defer func() { done(err) }()
+
+	return i.db.UpdateOne(ctx, filter, update, opts)
+}
+
+func (i *Instrumented) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts interface{}) (n int64, err error) {
+	ctx, done := i.start(ctx, "update_many")
+	defer func() { done(err) }()
+
+	return i.db.UpdateMany(ctx, filter, update, opts)
+}
+
+func (i *Instrumented) Aggregate(
+	ctx context.Context,
+	pipeline interface{},
+	opts interface{},
+	dataType interface{},
+) (res interface{}, err error) {
+	ctx, done := i.start(ctx, "aggregate")
+	defer func() { done(err) }()
+
+	return i.db.Aggregate(ctx, pipeline, opts, dataType)
+}
+
+func (i *Instrumented) CountDocuments(ctx context.Context, filter interface{}, opts interface{}) (n int64, err error) {
+	ctx, done := i.start(ctx, "count_documents")
+	defer func() { done(err) }()
+
+	return i.db.CountDocuments(ctx, filter, opts)
+}
+
+func (i *Instrumented) DeleteMany(ctx context.Context, filter interface{}, opts interface{}) (n int64, err error) {
+	ctx, done := i.start(ctx, "delete_many")
+	defer func() { done(err) }()
+
+	return i.db.DeleteMany(ctx, filter, opts)
+}
@@ -0,0 +1,51 @@
+package database_test
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/database/mocks"
+	"go.sport-news/internal/metrics"
+	"testing"
+)
+
+func TestInstrumented(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	db := mocks.NewDB(t)
+	runs := mocks.NewDB(t)
+	db.On("WithCollection", "runs").Return(runs)
+	db.On("CountDocuments", mock.Anything, nil, nil).Return(int64(2), nil)
+	runs.On("FindOne", mock.Anything, nil, nil, nil).Return(nil, mongo.ErrNoDocuments)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	i := database.Instrument(db, "news")
+	n, err := i.CountDocuments(ctx, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	_, err = i.WithCollection("runs").FindOne(ctx, nil, nil, nil)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	parent.End()
+
+	count, err := testutil.GatherAndCount(metrics.Registry(), "sport_news_mongo_operation_duration_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	spans := sr.Ended()
+	if assert.Len(t, spans, 3) {
+		assert.Equal(t, "mongo.count_documents", spans[0].Name())
+		assert.Equal(t, "mongo.find_one", spans[1].Name())
+		// not found isn't an error of the operation
+		assert.Equal(t, codes.Unset, spans[1].Status().Code)
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	}
+}
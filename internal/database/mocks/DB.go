@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+	database "go.sport-news/internal/database"
 )
 
 // DB is an autogenerated mock type for the DB type
@@ -13,6 +14,92 @@ type DB struct {
 	mock.Mock
 }
 
+// Aggregate provides a mock function with given fields: ctx, pipeline, opts, dataType
+func (_m *DB) Aggregate(ctx context.Context, pipeline interface{}, opts interface{}, dataType interface{}) (interface{}, error) {
+	ret := _m.Called(ctx, pipeline, opts, dataType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Aggregate")
+	}
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, interface{}) (interface{}, error)); ok {
+		return rf(ctx, pipeline, opts, dataType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, interface{}) interface{}); ok {
+		r0 = rf(ctx, pipeline, opts, dataType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, interface{}, interface{}) error); ok {
+		r1 = rf(ctx, pipeline, opts, dataType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BulkUpsert provides a mock function with given fields: ctx, filters, updates
+func (_m *DB) BulkUpsert(ctx context.Context, filters []interface{}, updates []interface{}) (int64, error) {
+	ret := _m.Called(ctx, filters, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkUpsert")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []interface{}, []interface{}) (int64, error)); ok {
+		return rf(ctx, filters, updates)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []interface{}, []interface{}) int64); ok {
+		r0 = rf(ctx, filters, updates)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []interface{}, []interface{}) error); ok {
+		r1 = rf(ctx, filters, updates)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountDocuments provides a mock function with given fields: ctx, filter, opts
+func (_m *DB) CountDocuments(ctx context.Context, filter interface{}, opts interface{}) (int64, error) {
+	ret := _m.Called(ctx, filter, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountDocuments")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}) (int64, error)); ok {
+		return rf(ctx, filter, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}) int64); ok {
+		r0 = rf(ctx, filter, opts)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, interface{}) error); ok {
+		r1 = rf(ctx, filter, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteMany provides a mock function with given fields: ctx, filter, opts
 func (_m *DB) DeleteMany(ctx context.Context, filter interface{}, opts interface{}) (int64, error) {
 	ret := _m.Called(ctx, filter, opts)
@@ -46,6 +133,42 @@ func (_m *DB) Disconnect(ctx context.Context) {
 	_m.Called(ctx)
 }
 
+// DropIndex provides a mock function with given fields: ctx, name
+func (_m *DB) DropIndex(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DropIndex")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureIndexes provides a mock function with given fields: ctx
+func (_m *DB) EnsureIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnsureIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Find provides a mock function with given fields: ctx, filter, opts, dataType
 func (_m *DB) Find(ctx context.Context, filter interface{}, opts interface{}, dataType interface{}) (interface{}, error) {
 	ret := _m.Called(ctx, filter, opts, dataType)
@@ -124,6 +247,100 @@ func (_m *DB) InsertMany(ctx context.Context, documents []interface{}) error {
 	return r0
 }
 
+// Ping provides a mock function with given fields: ctx
+func (_m *DB) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateMany provides a mock function with given fields: ctx, filter, update, opts
+func (_m *DB) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts interface{}) (int64, error) {
+	ret := _m.Called(ctx, filter, update, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMany")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, interface{}) (int64, error)); ok {
+		return rf(ctx, filter, update, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, interface{}) int64); ok {
+		r0 = rf(ctx, filter, update, opts)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, interface{}, interface{}) error); ok {
+		r1 = rf(ctx, filter, update, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOne provides a mock function with given fields: ctx, filter, update, opts
+func (_m *DB) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts interface{}) (int64, error) {
+	ret := _m.Called(ctx, filter, update, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOne")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, interface{}) (int64, error)); ok {
+		return rf(ctx, filter, update, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, interface{}) int64); ok {
+		r0 = rf(ctx, filter, update, opts)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, interface{}, interface{}) error); ok {
+		r1 = rf(ctx, filter, update, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithCollection provides a mock function with given fields: name
+func (_m *DB) WithCollection(name string) database.DB {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithCollection")
+	}
+
+	var r0 database.DB
+	if rf, ok := ret.Get(0).(func(string) database.DB); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(database.DB)
+		}
+	}
+
+	return r0
+}
+
 // NewDB creates a new instance of DB. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDB(t interface {
@@ -2,6 +2,9 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.sport-news/internal/config"
@@ -9,14 +12,26 @@ import (
 )
 
 type Mongo struct {
-	Client *mongo.Client
-	cfg    config.Mongo
+	Client     *mongo.Client
+	cfg        config.Mongo
+	collection string
 }
 
 const (
 	articles string = "articles"
+	// ArticleIDMigrations old to new article id mapping.
+	ArticleIDMigrations string = "article_id_migrations"
+	// IngestRuns history of the scheduler runs.
+	IngestRuns string = "ingest_runs"
+	// BackfillProgress progress of the backfill command.
+	BackfillProgress string = "backfill_progress"
+	// ArticleTextIndex full text index of the articles.
+	ArticleTextIndex string = "article_text"
 )
 
+// indexNotFound mongo error code of dropping a missing index.
+const indexNotFound = 27
+
 // MustLoad return new database without errors.
 func MustLoad(ctx context.Context, logger *zap.Logger, cfg config.Mongo) DB {
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.URL))
@@ -29,9 +44,101 @@ func MustLoad(ctx context.Context, logger *zap.Logger, cfg config.Mongo) DB {
 		logger.Fatal("failed to ping", zap.Error(err))
 	}
 
+	m := &Mongo{
+		Client:     client,
+		cfg:        cfg,
+		collection: articles,
+	}
+	// duplicates of the old data fail the unique indexes and the old text index conflicts,
+	// the service works without them until the migrations are run
+	if err = m.EnsureIndexes(ctx); err != nil {
+		logger.Error("failed to ensure indexes, run the dedupe-articles and text-index migrations", zap.Error(err))
+	}
+
+	return Instrument(m, articles)
+}
+
+// EnsureIndexes create indexes, existing ones are left as is.
+func (m *Mongo) EnsureIndexes(ctx context.Context) error {
+	_, err := m.Client.Database(m.cfg.Collection).Collection(articles).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// external ids are unique only inside a team
+			Keys:    bson.D{{Key: "teamId", Value: 1}, {Key: "externalId", Value: 1}},
+			Options: options.Index().SetName("team_external_id").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetName("id").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "teamId", Value: 1}, {Key: "published", Value: -1}},
+			Options: options.Index().SetName("team_published"),
+		},
+		{
+			Keys:    bson.D{{Key: "teamId", Value: 1}, {Key: "categories.slug", Value: 1}},
+			Options: options.Index().SetName("team_category"),
+		},
+		{
+			// plain text of the content, so markup and urls aren't matched
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "teaser", Value: "text"}, {Key: "contentText", Value: "text"}},
+			Options: options.Index().SetName(ArticleTextIndex).SetWeights(bson.D{
+				{Key: "title", Value: 10},
+				{Key: "teaser", Value: 5},
+				{Key: "contentText", Value: 1},
+			}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	runs := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetName("id").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "teamId", Value: 1}, {Key: "startedAt", Value: -1}},
+			Options: options.Index().SetName("team_started"),
+		},
+	}
+	if m.cfg.IngestRunsTTL > 0 {
+		// old runs are removed by mongo
+		runs = append(runs, mongo.IndexModel{
+			Keys:    bson.D{{Key: "startedAt", Value: 1}},
+			Options: options.Index().SetName("started_ttl").SetExpireAfterSeconds(int32(m.cfg.IngestRunsTTL.Seconds())),
+		})
+	}
+	_, err = m.Client.Database(m.cfg.Collection).Collection(IngestRuns).Indexes().CreateMany(ctx, runs)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.Client.Database(m.cfg.Collection).Collection(BackfillProgress).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetName("id").SetUnique(true),
+	})
+
+	return err
+}
+
+// DropIndex drop index of the collection by name, a missing index isn't an error.
+func (m *Mongo) DropIndex(ctx context.Context, name string) error {
+	_, err := m.Client.Database(m.cfg.Collection).Collection(m.collection).Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == indexNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// WithCollection return db which works with another collection on the same connection.
+func (m *Mongo) WithCollection(name string) DB {
 	return &Mongo{
-		Client: client,
-		cfg:    cfg,
+		Client:     m.Client,
+		cfg:        m.cfg,
+		collection: name,
 	}
 }
 
@@ -42,9 +149,14 @@ func (m *Mongo) Disconnect(ctx context.Context) {
 	}
 }
 
+// Ping check the connection to the primary.
+func (m *Mongo) Ping(ctx context.Context) error {
+	return m.Client.Ping(ctx, nil)
+}
+
 // InsertMany insert rows to db.
 func (m *Mongo) InsertMany(ctx context.Context, documents []interface{}) error {
-	_, err := m.Client.Database(m.cfg.Collection).Collection(articles).InsertMany(ctx, documents)
+	_, err := m.Client.Database(m.cfg.Collection).Collection(m.collection).InsertMany(ctx, documents)
 	return err
 }
 
@@ -58,7 +170,28 @@ func (m *Mongo) Find(ctx context.Context, filter interface{}, opts interface{},
 		opt = opts.(*options.FindOptions)
 	}
 
-	c, err := m.Client.Database(m.cfg.Collection).Collection(articles).Find(ctx, filter, opt)
+	c, err := m.Client.Database(m.cfg.Collection).Collection(m.collection).Find(ctx, filter, opt)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.All(ctx, dataType); err != nil {
+		return nil, err
+	}
+
+	return dataType, nil
+}
+
+// Aggregate run aggregation pipeline.
+// pipeline mongo.Pipeline{} or bson.A{} param
+// opts *options.AggregateOptions
+// dataType struct want to be struct.
+func (m *Mongo) Aggregate(ctx context.Context, pipeline interface{}, opts interface{}, dataType interface{}) (interface{}, error) {
+	var opt *options.AggregateOptions
+	if opts != nil {
+		opt = opts.(*options.AggregateOptions)
+	}
+
+	c, err := m.Client.Database(m.cfg.Collection).Collection(m.collection).Aggregate(ctx, pipeline, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +212,7 @@ func (m *Mongo) FindOne(ctx context.Context, filter interface{}, opts interface{
 		opt = opts.(*options.FindOneOptions)
 	}
 
-	c := m.Client.Database(m.cfg.Collection).Collection(articles).FindOne(ctx, filter, opt)
+	c := m.Client.Database(m.cfg.Collection).Collection(m.collection).FindOne(ctx, filter, opt)
 	if c.Err() != nil {
 		return nil, c.Err()
 	}
@@ -91,6 +224,86 @@ func (m *Mongo) FindOne(ctx context.Context, filter interface{}, opts interface{
 	return dataType, nil
 }
 
+// BulkUpsert upsert rows in one batch, filters[i] is the filter of updates[i].
+// Returns count of modified and upserted rows.
+// filters bson.D{} params
+// updates bson.D{} with update operators
+func (m *Mongo) BulkUpsert(ctx context.Context, filters []interface{}, updates []interface{}) (int64, error) {
+	if len(filters) != len(updates) {
+		return 0, fmt.Errorf("bulk upsert: %d filters for %d updates", len(filters), len(updates))
+	}
+	if len(filters) == 0 {
+		return 0, nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(filters))
+	for i := range filters {
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filters[i]).SetUpdate(updates[i]).SetUpsert(true))
+	}
+
+	c := m.Client.Database(m.cfg.Collection).Collection(m.collection)
+	opt := options.BulkWrite().SetOrdered(false)
+
+	r, err := c.BulkWrite(ctx, models, opt)
+	if mongo.IsDuplicateKeyError(err) {
+		// concurrent upsert inserted the same row, second try matches it
+		r, err = c.BulkWrite(ctx, models, opt)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return r.ModifiedCount + r.UpsertedCount, nil
+}
+
+// UpdateOne update one row, returns count of modified and upserted rows.
+// filter bson.D{} param
+// update bson.D{} with update operators
+// opts *options.UpdateOptions
+func (m *Mongo) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts interface{}) (int64, error) {
+	var opt *options.UpdateOptions
+	if opts != nil {
+		opt = opts.(*options.UpdateOptions)
+	}
+
+	c, err := m.Client.Database(m.cfg.Collection).Collection(m.collection).UpdateOne(ctx, filter, update, opt)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.ModifiedCount + c.UpsertedCount, nil
+}
+
+// UpdateMany update many rows, returns count of modified and upserted rows.
+// filter bson.D{} param
+// update bson.D{} with update operators
+// opts *options.UpdateOptions
+func (m *Mongo) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts interface{}) (int64, error) {
+	var opt *options.UpdateOptions
+	if opts != nil {
+		opt = opts.(*options.UpdateOptions)
+	}
+
+	c, err := m.Client.Database(m.cfg.Collection).Collection(m.collection).UpdateMany(ctx, filter, update, opt)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.ModifiedCount + c.UpsertedCount, nil
+}
+
+// CountDocuments count rows matching the filter.
+// filter bson.D{} param
+// opts *options.CountOptions
+func (m *Mongo) CountDocuments(ctx context.Context, filter interface{}, opts interface{}) (int64, error) {
+	var opt *options.CountOptions
+	if opts != nil {
+		opt = opts.(*options.CountOptions)
+	}
+
+	return m.Client.Database(m.cfg.Collection).Collection(m.collection).CountDocuments(ctx, filter, opt)
+}
+
 // DeleteMany delete many rows.
 // filter bson.D{} param
 // opts *options.DeleteOptions
@@ -100,7 +313,7 @@ func (m *Mongo) DeleteMany(ctx context.Context, filter interface{}, opts interfa
 		opt = opts.(*options.DeleteOptions)
 	}
 
-	c, err := m.Client.Database(m.cfg.Collection).Collection(articles).DeleteMany(ctx, filter, opt)
+	c, err := m.Client.Database(m.cfg.Collection).Collection(m.collection).DeleteMany(ctx, filter, opt)
 	if err != nil {
 		return 0, err
 	}
@@ -1,25 +1,56 @@
 package entity
 
 import (
+	"fmt"
+	"github.com/google/uuid"
 	"time"
 )
 
 // DefaultTeamId default for field Article -> TeamID.
 const DefaultTeamId = "t94"
 
+// articleNamespace namespace of the name based article ids, must never change.
+var articleNamespace = uuid.MustParse("7492b0ae-42e2-4151-972f-af90342784fa")
+
+// ArticleID return stable id of the team article with the external id,
+// re-ingesting the article gives the same id.
+func ArticleID(team string, externalId int) string {
+	return uuid.NewSHA1(articleNamespace, []byte(fmt.Sprintf("%s:%d", team, externalId))).String()
+}
+
+// Reasons why an article was retracted, stored in Article -> DeleteReason.
+const (
+	DeleteReasonUnpublished = "unpublished"
+	DeleteReasonMissing     = "missing from feed"
+)
+
 // Article it's a full article entity.
 type Article struct {
-	ID          string    `bson:"id" json:"id"`
-	TeamID      string    `bson:"teamId" json:"teamId"`
-	ExternalId  int       `bson:"externalId" json:"-"`
-	OptaMatchID *string   `bson:"optaMatchId,omitempty" json:"optaMatchId,omitempty"`
-	Title       string    `bson:"title" json:"title"`
-	Type        []string  `bson:"type" json:"type"`
-	Teaser      string    `bson:"teaser" json:"teaser"`
-	Content     string    `bson:"content" json:"content"`
+	ID          string     `bson:"id" json:"id"`
+	TeamID      string     `bson:"teamId" json:"teamId"`
+	ExternalId  int        `bson:"externalId" json:"-"`
+	OptaMatchID *string    `bson:"optaMatchId,omitempty" json:"optaMatchId,omitempty"`
+	Title       string     `bson:"title" json:"title"`
+	Subtitle    string     `bson:"subtitle" json:"subtitle"`
+	Type        []string   `bson:"type" json:"type"`
+	Categories  []Category `bson:"categories" json:"categories"`
+	Teaser      string     `bson:"teaser" json:"teaser"`
+	Content     string     `bson:"content" json:"content"`
+	// ContentText and Blocks are plain text and structured renditions of Content.
+	ContentText string    `bson:"contentText" json:"-"`
+	Blocks      []Block   `bson:"blocks,omitempty" json:"-"`
 	URL         string    `bson:"url" json:"url"`
 	ImageURL    string    `bson:"imageUrl" json:"imageUrl"`
-	GalleryUrls any       `bson:"galleryUrls,omitempty" json:"galleryUrls"`
-	VideoURL    any       `bson:"videoUrl,omitempty" json:"videoUrl"`
+	GalleryUrls MediaList `bson:"galleryUrls,omitempty" json:"galleryUrls"`
+	VideoURL    MediaList `bson:"videoUrl,omitempty" json:"videoUrl"`
 	Published   time.Time `bson:"published" json:"published"`
+	Updated     time.Time `bson:"updated" json:"updated"`
+	// ClubName and ClubWebsiteURL attribute the article to the club feed.
+	ClubName       string `bson:"clubName" json:"clubName"`
+	ClubWebsiteURL string `bson:"clubWebsiteUrl" json:"clubWebsiteUrl"`
+	// LastSeen last time the article was in the feed list.
+	LastSeen time.Time `bson:"lastSeen" json:"-"`
+	// DeletedAt and DeleteReason are set when the article is retracted upstream.
+	DeletedAt    *time.Time `bson:"deletedAt,omitempty" json:"-"`
+	DeleteReason string     `bson:"deleteReason,omitempty" json:"-"`
 }
@@ -0,0 +1,12 @@
+package entity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestArticleID(t *testing.T) {
+	// ids are public links, they must not change between releases
+	assert.Equal(t, "3a8434dd-7593-5b1a-81ec-9d1dde90e6a4", ArticleID("t94", 653887))
+	assert.NotEqual(t, ArticleID("t94", 653887), ArticleID("t1", 653887))
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// BackfillProgress it's a progress of the backfill of one team,
+// articles are walked in order of external id, so the next run goes on after LastExternalId.
+// Articles which failed to fetch are kept in FailedIds and retried after the walk.
+type BackfillProgress struct {
+	// ID team with the hash of the filter.
+	ID             string     `bson:"id" json:"id"`
+	TeamID         string     `bson:"teamId" json:"teamId"`
+	LastExternalId int        `bson:"lastExternalId" json:"lastExternalId"`
+	Processed      int        `bson:"processed" json:"processed"`
+	Changed        int        `bson:"changed" json:"changed"`
+	Upserted       int64      `bson:"upserted" json:"upserted"`
+	Retracted      int64      `bson:"retracted" json:"retracted"`
+	Failed         int        `bson:"failed" json:"failed"`
+	FailedIds      []int      `bson:"failedIds,omitempty" json:"failedIds,omitempty"`
+	StartedAt      time.Time  `bson:"startedAt" json:"startedAt"`
+	UpdatedAt      time.Time  `bson:"updatedAt" json:"updatedAt"`
+	FinishedAt     *time.Time `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+// Types of Block.
+const (
+	BlockParagraph = "paragraph"
+	BlockHeading   = "heading"
+	BlockImage     = "image"
+	BlockEmbed     = "embed"
+)
+
+// Block it's a structured piece of the article content.
+type Block struct {
+	Type string `bson:"type" json:"type"`
+	// Text plain text of paragraph and heading.
+	Text string `bson:"text,omitempty" json:"text,omitempty"`
+	// HTML sanitized inline html of paragraph, keeps links and emphasis.
+	HTML string `bson:"html,omitempty" json:"html,omitempty"`
+	// Level of heading, 1 to 6.
+	Level int `bson:"level,omitempty" json:"level,omitempty"`
+	// Media of image and embed.
+	Media *Media `bson:"media,omitempty" json:"media,omitempty"`
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Category of the article parsed from the feed taxonomies.
+type Category struct {
+	Slug string `bson:"slug" json:"slug"`
+	Name string `bson:"name" json:"name"`
+}
+
+// ParseCategories split taxonomy values like "Club News, First Team"
+// to distinct categories, the first spelling of a name wins.
+func ParseCategories(values ...string) []Category {
+	var (
+		res  []Category
+		seen = make(map[string]struct{})
+	)
+	for _, v := range values {
+		for _, name := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' || r == '|' }) {
+			name = strings.Join(strings.Fields(name), " ")
+			slug := Slug(name)
+			if slug == "" {
+				continue
+			}
+			if _, ok := seen[slug]; ok {
+				continue
+			}
+			seen[slug] = struct{}{}
+			res = append(res, Category{Slug: slug, Name: name})
+		}
+	}
+
+	return res
+}
+
+// CategoryNames return display names of categories.
+func CategoryNames(categories []Category) []string {
+	names := make([]string, 0, len(categories))
+	for _, c := range categories {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+// Slug make url friendly lower case name, "First Team" is "first-team".
+func Slug(name string) string {
+	var (
+		b    strings.Builder
+		dash bool
+	)
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+
+	return b.String()
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseCategories(t *testing.T) {
+	assert.Equal(t, []Category{
+		{Slug: "club-news", Name: "Club News"},
+		{Slug: "first-team", Name: "First Team"},
+		{Slug: "u21s", Name: "U21s"},
+	}, ParseCategories("Club News, First  Team", "club news;U21s|", " , "))
+	assert.Nil(t, ParseCategories(""))
+	assert.Equal(t, "academy-under-18s", Slug(" Academy: Under-18s "))
+}
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// Statuses of IngestRun.
+const (
+	IngestRunning     = "running"
+	IngestSuccess     = "success"
+	IngestFailed      = "failed"
+	IngestNotModified = "not_modified"
+)
+
+// IngestRun it's a record of one scheduler run of a feed source.
+type IngestRun struct {
+	ID         string     `bson:"id" json:"id"`
+	TeamID     string     `bson:"teamId" json:"teamId"`
+	Provider   string     `bson:"provider" json:"provider"`
+	StartedAt  time.Time  `bson:"startedAt" json:"startedAt"`
+	FinishedAt *time.Time `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+	Status     string     `bson:"status" json:"status"`
+	// Error why the run failed.
+	Error string `bson:"error,omitempty" json:"error,omitempty"`
+	// Fetched articles in the feed list, New and Updated of them were fetched in full.
+	Fetched   int   `bson:"fetched" json:"fetched"`
+	New       int   `bson:"new" json:"new"`
+	Updated   int   `bson:"updated" json:"updated"`
+	Upserted  int64 `bson:"upserted" json:"upserted"`
+	Retracted int64 `bson:"retracted" json:"retracted"`
+	Failed    int   `bson:"failed" json:"failed"`
+	// Errors of the articles which failed.
+	Errors []IngestError `bson:"errors,omitempty" json:"errors,omitempty"`
+}
+
+// IngestError error of one feed article.
+type IngestError struct {
+	ExternalId int    `bson:"externalId" json:"externalId"`
+	Error      string `bson:"error" json:"error"`
+}
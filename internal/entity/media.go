@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kinds of Media.
+const (
+	MediaImage   = "image"
+	MediaVideo   = "video"
+	MediaYouTube = "youtube"
+	MediaVimeo   = "vimeo"
+	MediaEmbed   = "embed"
+)
+
+// Media it's an image or a video of the article.
+type Media struct {
+	URL     string  `bson:"url" json:"url"`
+	Kind    string  `bson:"kind" json:"kind"`
+	Caption *string `bson:"caption,omitempty" json:"caption,omitempty"`
+	Width   *int    `bson:"width,omitempty" json:"width,omitempty"`
+	Height  *int    `bson:"height,omitempty" json:"height,omitempty"`
+}
+
+// MediaList media of the article. Documents stored before the media migration
+// hold the raw feed string, it's decoded as an empty list until the migration converts it.
+type MediaList []Media
+
+// UnmarshalBSONValue decode the list, a legacy string is an empty list.
+func (l *MediaList) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	switch t {
+	case bson.TypeString, bson.TypeNull, bson.TypeUndefined:
+		*l = nil
+		return nil
+	}
+
+	var m []Media
+	if err := bson.UnmarshalValue(t, data, &m); err != nil {
+		return err
+	}
+	*l = m
+
+	return nil
+}
+
+// ParseMedia parse delimited list of urls from the feed to media of the kind,
+// video hosting links get their own kind. Relative urls are resolved against base,
+// they are dropped when base is empty.
+func ParseMedia(raw, kind, base string) []Media {
+	var (
+		res  []Media
+		seen = make(map[string]struct{})
+	)
+	b, _ := url.Parse(base)
+
+	for _, s := range strings.FieldsFunc(html.UnescapeString(raw), func(r rune) bool {
+		return r == ',' || r == ';' || r == '|' || unicode.IsSpace(r)
+	}) {
+		u, err := url.Parse(s)
+		if err != nil {
+			continue
+		}
+		if !u.IsAbs() {
+			if b == nil || !b.IsAbs() {
+				continue
+			}
+			u = b.ResolveReference(u)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			continue
+		}
+		if _, ok := seen[u.String()]; ok {
+			continue
+		}
+		seen[u.String()] = struct{}{}
+
+		res = append(res, NewMedia(u.String(), kind))
+	}
+
+	return res
+}
+
+// NewMedia make media with the url, kind is detected for video hostings,
+// dimensions are taken from width and height url params.
+func NewMedia(link, kind string) Media {
+	m := Media{URL: link, Kind: kind}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return m
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch {
+	case host == "youtu.be" || host == "youtube.com" || strings.HasSuffix(host, ".youtube.com"):
+		m.Kind = MediaYouTube
+	case host == "vimeo.com" || strings.HasSuffix(host, ".vimeo.com"):
+		m.Kind = MediaVimeo
+	}
+
+	q := u.Query()
+	if w, err := strconv.Atoi(q.Get("width")); err == nil && w > 0 {
+		m.Width = &w
+	}
+	if h, err := strconv.Atoi(q.Get("height")); err == nil && h > 0 {
+		m.Height = &h
+	}
+
+	return m
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+)
+
+func TestParseMedia(t *testing.T) {
+	w, h := 1920, 1080
+
+	assert.Equal(t, []Media{
+		{URL: "https://www.htafc.com/api/image/1.jpg", Kind: MediaImage},
+		{URL: "https://cdn.test/2.jpg?height=1080&width=1920", Kind: MediaImage, Width: &w, Height: &h},
+	}, ParseMedia(
+		"/api/image/1.jpg, https://cdn.test/2.jpg?height=1080&amp;width=1920;\n/api/image/1.jpg | javascript:alert(1)",
+		MediaImage,
+		"https://www.htafc.com",
+	))
+
+	assert.Equal(t, []Media{
+		{URL: "https://youtu.be/abc", Kind: MediaYouTube},
+		{URL: "https://player.vimeo.com/video/1", Kind: MediaVimeo},
+		{URL: "https://cdn.test/v.mp4", Kind: MediaVideo},
+	}, ParseMedia("https://youtu.be/abc https://player.vimeo.com/video/1 https://cdn.test/v.mp4 /relative.mp4", MediaVideo, ""))
+
+	assert.Nil(t, ParseMedia("", MediaImage, "https://www.htafc.com"))
+}
+
+func TestMediaList_UnmarshalBSONValue(t *testing.T) {
+	legacy, err := bson.Marshal(bson.D{
+		{Key: "galleryUrls", Value: ""},
+		{Key: "videoUrl", Value: "https://youtu.be/1"},
+	})
+	require.NoError(t, err)
+
+	// legacy strings are empty until the media migration
+	var a Article
+	require.NoError(t, bson.Unmarshal(legacy, &a))
+	assert.Empty(t, a.GalleryUrls)
+	assert.Empty(t, a.VideoURL)
+
+	stored, err := bson.Marshal(Article{VideoURL: MediaList{NewMedia("https://youtu.be/1", MediaVideo)}})
+	require.NoError(t, err)
+
+	var b Article
+	require.NoError(t, bson.Unmarshal(stored, &b))
+	assert.Equal(t, MediaList{{URL: "https://youtu.be/1", Kind: MediaYouTube}}, b.VideoURL)
+	assert.Nil(t, b.GalleryUrls)
+}
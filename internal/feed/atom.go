@@ -0,0 +1,149 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr"`
+	Type  string `xml:"type,attr"`
+	Title string `xml:"title,attr"`
+}
+
+type atomEntry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Summary    string     `xml:"summary"`
+	Content    string     `xml:"content"`
+	Published  string     `xml:"published"`
+	Updated    string     `xml:"updated"`
+	Links      []atomLink `xml:"link"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+}
+
+// AtomProvider reads Atom feed, the list carries full articles.
+type AtomProvider struct {
+	logger *zap.Logger
+	src    config.Source
+	opts   Options
+}
+
+func NewAtom(logger *zap.Logger, src config.Source, opts Options) *AtomProvider {
+	return &AtomProvider{logger: logger, src: src, opts: opts}
+}
+
+// List return the latest entries of the feed.
+func (p *AtomProvider) List(ctx context.Context) ([]Item, error) {
+	var f atomFeed
+	if err := p.opts.Fetcher.GetConditional(ctx, p.opts.ListRetry, p.src.URL, &f); err != nil {
+		return nil, fmt.Errorf("failed do request %s: %w", p.src.URL, err)
+	}
+
+	var site string
+	for _, l := range f.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			site = l.Href
+			break
+		}
+	}
+
+	items := make([]Item, 0, len(f.Entries))
+	for _, e := range f.Entries {
+		if p.src.Count > 0 && len(items) == p.src.Count {
+			break
+		}
+
+		updated, err := parseDate([]string{time.RFC3339}, e.Updated)
+		if err != nil {
+			p.logger.Error("failed parse date", zap.Error(err), zap.String("data", e.Updated))
+			continue
+		}
+		published := updated
+		if e.Published != "" {
+			if published, err = parseDate([]string{time.RFC3339}, e.Published); err != nil {
+				p.logger.Error("failed parse date", zap.Error(err), zap.String("data", e.Published))
+				continue
+			}
+		}
+
+		a := entity.Article{
+			TeamID:         p.src.Team,
+			ExternalId:     externalID(e.ID),
+			Title:          strings.TrimSpace(e.Title),
+			Teaser:         e.Summary,
+			Content:        e.Content,
+			Published:      published,
+			Updated:        updated,
+			ClubName:       strings.TrimSpace(f.Title),
+			ClubWebsiteURL: site,
+		}
+		if a.Content == "" {
+			a.Content = e.Summary
+		}
+		names := make([]string, 0, len(e.Categories))
+		for _, c := range e.Categories {
+			if c.Label != "" {
+				names = append(names, c.Label)
+			} else {
+				names = append(names, c.Term)
+			}
+		}
+		a.Categories = entity.ParseCategories(names...)
+		a.Type = entity.CategoryNames(a.Categories)
+		for _, l := range e.Links {
+			switch {
+			case (l.Rel == "" || l.Rel == "alternate") && a.URL == "":
+				a.URL = l.Href
+			case l.Rel == "enclosure" && strings.HasPrefix(l.Type, "image/"):
+				a.GalleryUrls = append(a.GalleryUrls, enclosure(l.Href, l.Title, entity.MediaImage))
+			case l.Rel == "enclosure" && strings.HasPrefix(l.Type, "video/"):
+				a.VideoURL = append(a.VideoURL, enclosure(l.Href, l.Title, entity.MediaVideo))
+			}
+		}
+		if len(a.GalleryUrls) > 0 {
+			a.ImageURL = a.GalleryUrls[0].URL
+		}
+
+		items = append(items, Item{Article: a, IsPublished: true})
+	}
+
+	return items, nil
+}
+
+// Forget the last feed response.
+func (p *AtomProvider) Forget() {
+	p.opts.Fetcher.Forget(p.src.URL)
+}
+
+// Article return the entry from the list, Atom has no details.
+func (p *AtomProvider) Article(_ context.Context, item Item) (entity.Article, error) {
+	return item.Article, nil
+}
+
+// enclosure make media from the enclosure link, title is the caption.
+func enclosure(href, title, kind string) entity.Media {
+	m := entity.NewMedia(href, kind)
+	if title = strings.TrimSpace(title); title != "" {
+		m.Caption = &title
+	}
+
+	return m
+}
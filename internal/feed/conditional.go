@@ -0,0 +1,108 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"go.sport-news/internal/metrics"
+	"net/http"
+)
+
+// ErrNotModified returned by Fetcher.GetConditional when the url didn't change since the last response.
+var ErrNotModified = errors.New("not modified")
+
+// validator of the last response of the url.
+type validator struct {
+	etag         string
+	lastModified string
+}
+
+// ConditionalStats counts of the conditional requests to a host,
+// Hits are 304 responses and Misses are full downloads.
+type ConditionalStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// GetConditional do Get with If-None-Match and If-Modified-Since of the last response of the url,
+// returns ErrNotModified on 304.
+func (f *Fetcher) GetConditional(ctx context.Context, policy RetryPolicy, url string, data any) error {
+	return f.get(ctx, policy, url, data, true)
+}
+
+// Forget drop validators of the url, so the next request downloads it in full.
+// Used when the downloaded data was not processed.
+func (f *Fetcher) Forget(url string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.validators, url)
+}
+
+// Stats return conditional request counts by host.
+func (f *Fetcher) Stats() map[string]ConditionalStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	res := make(map[string]ConditionalStats, len(f.stats))
+	for host, s := range f.stats {
+		res[host] = *s
+	}
+
+	return res
+}
+
+// condition set validators of the url to the request.
+func (f *Fetcher) condition(req *http.Request, url string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	v, ok := f.validators[url]
+	if !ok {
+		return
+	}
+	if v.etag != "" {
+		req.Header.Set("If-None-Match", v.etag)
+	}
+	if v.lastModified != "" {
+		req.Header.Set("If-Modified-Since", v.lastModified)
+	}
+}
+
+// remember validators of the full response and count a miss.
+func (f *Fetcher) remember(url string, h http.Header) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	v := validator{etag: h.Get("ETag"), lastModified: h.Get("Last-Modified")}
+	if v.etag != "" || v.lastModified != "" {
+		f.validators[url] = v
+	} else {
+		delete(f.validators, url)
+	}
+
+	f.stat(url).Misses++
+	metrics.FeedConditional(hostOf(url), false)
+}
+
+// hit count 304 response.
+func (f *Fetcher) hit(url string) ConditionalStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.stat(url)
+	s.Hits++
+	metrics.FeedConditional(hostOf(url), true)
+
+	return *s
+}
+
+func (f *Fetcher) stat(url string) *ConditionalStats {
+	host := hostOf(url)
+	s, ok := f.stats[host]
+	if !ok {
+		s = &ConditionalStats{}
+		f.stats[host] = s
+	}
+
+	return s
+}
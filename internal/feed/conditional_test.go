@@ -0,0 +1,45 @@
+package feed
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.sport-news/internal/config"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetcher_GetConditional(t *testing.T) {
+	const lastModified = "Wed, 28 Feb 2024 10:00:00 GMT"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = w.Write([]byte(rssBody))
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(zap.NewNop(), srv.Client(), nil, "")
+	p := NewRSS(zap.NewNop(), config.Source{Team: "t1", URL: srv.URL}, Options{Fetcher: f})
+
+	items, err := p.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	_, err = p.List(context.Background())
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	var data rssFeed
+	assert.NoError(t, f.Get(context.Background(), RetryPolicy{}, srv.URL, &data), "plain get is not conditional")
+
+	p.Forget()
+	items, err = p.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	assert.Equal(t, map[string]ConditionalStats{hostOf(srv.URL): {Hits: 1, Misses: 2}}, f.Stats())
+}
@@ -0,0 +1,80 @@
+// Package feed fetches club news feeds and normalizes them to entity.Article.
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+	"hash/fnv"
+	"math"
+)
+
+// Available providers for config.Source -> Provider.
+const (
+	InCrowd = "incrowd"
+	RSS     = "rss"
+	Atom    = "atom"
+)
+
+// ErrUnpublished returned by Provider.Article when the article was unpublished upstream.
+var ErrUnpublished = errors.New("article is unpublished")
+
+// Item it's an article from the feed list.
+type Item struct {
+	// Article normalized list data, providers which have no details
+	// endpoint put the full article here.
+	Article     entity.Article
+	IsPublished bool
+}
+
+// Provider of the club news feed.
+type Provider interface {
+	// List return the latest articles of the feed,
+	// ErrNotModified when the feed didn't change since the last List.
+	List(ctx context.Context) ([]Item, error)
+	// Forget the last List, so the next one returns the feed even if it didn't change.
+	Forget()
+	// Article return the full article for the list item.
+	Article(ctx context.Context, item Item) (entity.Article, error)
+}
+
+// Options of the feed requests, shared by the providers of all sources.
+type Options struct {
+	Fetcher      *Fetcher
+	ListRetry    RetryPolicy
+	ArticleRetry RetryPolicy
+}
+
+// NewOptions make request options with the fetcher and retry policies from the parser config.
+func NewOptions(cfg config.Parser, fetcher *Fetcher) Options {
+	return Options{
+		Fetcher:      fetcher,
+		ListRetry:    NewRetryPolicy(cfg.ListRetry),
+		ArticleRetry: NewRetryPolicy(cfg.ArticleRetry),
+	}
+}
+
+// New return provider for the source.
+func New(logger *zap.Logger, src config.Source, opts Options) (Provider, error) {
+	switch src.Provider {
+	case InCrowd, "":
+		return NewInCrowd(logger, src, opts), nil
+	case RSS:
+		return NewRSS(logger, src, opts), nil
+	case Atom:
+		return NewAtom(logger, src, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown feed provider %q", src.Provider)
+	}
+}
+
+// externalID make numeric id from string id of the feeds which have no numeric ids.
+func externalID(id string) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+
+	return int(h.Sum64() & math.MaxInt64)
+}
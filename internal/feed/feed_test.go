@@ -0,0 +1,203 @@
+package feed
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const rssBody = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <title>Club</title>
+    <link>https://club.test</link>
+    <item>
+      <title> Match report </title>
+      <link>https://club.test/news/1</link>
+      <guid>https://club.test/news/1</guid>
+      <description>Teaser</description>
+      <content:encoded><![CDATA[<p>Body</p>]]></content:encoded>
+      <pubDate>Wed, 28 Feb 2024 09:58:47 +0000</pubDate>
+      <category>First Team</category>
+      <enclosure url="https://club.test/1.jpg" type="image/jpeg" length="1"/>
+    </item>
+    <item>
+      <title>Broken date</title>
+      <guid>2</guid>
+      <pubDate>yesterday</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+const atomBody = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Club</title>
+  <link rel="alternate" href="https://club.test"/>
+  <link rel="self" href="https://club.test/feed.atom"/>
+  <entry>
+    <id>urn:club:1</id>
+    <title>Signing</title>
+    <link rel="alternate" href="https://club.test/news/signing"/>
+    <link rel="enclosure" type="image/png" href="https://club.test/s.png" title="New signing"/>
+    <link rel="enclosure" type="video/mp4" href="https://youtu.be/abc"/>
+    <published>2024-02-28T09:58:47Z</published>
+    <updated>2024-02-28T10:03:13Z</updated>
+    <summary>Teaser</summary>
+    <content type="html">&lt;p&gt;Body&lt;/p&gt;</content>
+    <category term="club-news" label="Club News"/>
+  </entry>
+</feed>`
+
+const inCrowdArticleBody = `<?xml version="1.0" encoding="utf-8"?>
+<NewsArticleInformation>
+  <ClubName>Brentford</ClubName>
+  <ClubWebsiteURL>https://www.brentfordfc.com</ClubWebsiteURL>
+  <NewsArticle>
+    <ArticleURL>https://www.brentfordfc.com/news/1</ArticleURL>
+    <NewsArticleID>1</NewsArticleID>
+    <PublishDate>2024-02-28 09:58:47</PublishDate>
+    <Taxonomies>Club News</Taxonomies>
+    <TeaserText>Teaser</TeaserText>
+    <Subtitle>Subtitle</Subtitle>
+    <Title>Title</Title>
+    <BodyText>&lt;p&gt;Body&lt;/p&gt;</BodyText>
+    <GalleryImageURLs>/g/1.jpg</GalleryImageURLs>
+    <LastUpdateDate>2024-02-28 10:03:13</LastUpdateDate>
+    <IsPublished>True</IsPublished>
+  </NewsArticle>
+</NewsArticleInformation>`
+
+const inCrowdListBody = `<?xml version="1.0" encoding="utf-8"?>
+<NewListInformation>
+  <ClubName>Brentford</ClubName>
+  <NewsletterNewsItems>
+    <NewsletterNewsItem>
+      <NewsArticleID>1</NewsArticleID>
+      <PublishDate>2024-02-28 09:58:47</PublishDate>
+      <LastUpdateDate>2024-02-28 10:03:13</LastUpdateDate>
+    </NewsletterNewsItem>
+    <NewsletterNewsItem>
+      <NewsArticleID>2</NewsArticleID>
+      <PublishDate>2024-02-28 09:58:47</PublishDate>
+    </NewsletterNewsItem>
+    <NewsletterNewsItem>
+      <NewsArticleID>3</NewsArticleID>
+      <PublishDate>2024-02-28 09:58:47</PublishDate>
+      <LastUpdateDate>never</LastUpdateDate>
+    </NewsletterNewsItem>
+  </NewsletterNewsItems>
+</NewListInformation>`
+
+func serve(t *testing.T, body string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// options of the requests to the test server.
+func options(srv *httptest.Server) Options {
+	return Options{Fetcher: NewFetcher(zap.NewNop(), srv.Client(), nil, "")}
+}
+
+func TestRSSProvider_List(t *testing.T) {
+	srv := serve(t, rssBody)
+	p := NewRSS(zap.NewNop(), config.Source{Team: "t1", URL: srv.URL}, options(srv))
+
+	items, err := p.List(context.Background())
+	if !assert.NoError(t, err) || !assert.Len(t, items, 1) {
+		return
+	}
+
+	a := items[0].Article
+	assert.True(t, items[0].IsPublished)
+	assert.Equal(t, "t1", a.TeamID)
+	assert.Equal(t, externalID("https://club.test/news/1"), a.ExternalId)
+	assert.Equal(t, "Match report", a.Title)
+	assert.Equal(t, "<p>Body</p>", a.Content)
+	assert.Equal(t, "https://club.test/1.jpg", a.ImageURL)
+	assert.Equal(t, []string{"First Team"}, a.Type)
+	assert.Equal(t, []entity.Category{{Slug: "first-team", Name: "First Team"}}, a.Categories)
+	assert.Equal(t, "Club", a.ClubName)
+	assert.Equal(t, "https://club.test", a.ClubWebsiteURL)
+	assert.Equal(t, time.Date(2024, 2, 28, 9, 58, 47, 0, time.UTC), a.Published)
+}
+
+func TestAtomProvider_List(t *testing.T) {
+	srv := serve(t, atomBody)
+	p := NewAtom(zap.NewNop(), config.Source{Team: "t1", URL: srv.URL}, options(srv))
+
+	items, err := p.List(context.Background())
+	if !assert.NoError(t, err) || !assert.Len(t, items, 1) {
+		return
+	}
+
+	a := items[0].Article
+	assert.Equal(t, externalID("urn:club:1"), a.ExternalId)
+	assert.Equal(t, "https://club.test/news/signing", a.URL)
+	assert.Equal(t, "https://club.test/s.png", a.ImageURL)
+	caption := "New signing"
+	assert.Equal(t, entity.MediaList{{URL: "https://club.test/s.png", Kind: entity.MediaImage, Caption: &caption}}, a.GalleryUrls)
+	assert.Equal(t, entity.MediaList{{URL: "https://youtu.be/abc", Kind: entity.MediaYouTube}}, a.VideoURL)
+	assert.Equal(t, "<p>Body</p>", a.Content)
+	assert.Equal(t, []string{"Club News"}, a.Type)
+	assert.Equal(t, "Club", a.ClubName)
+	assert.Equal(t, "https://club.test", a.ClubWebsiteURL)
+	assert.Equal(t, time.Date(2024, 2, 28, 9, 58, 47, 0, time.UTC), a.Published)
+	assert.Equal(t, time.Date(2024, 2, 28, 10, 3, 13, 0, time.UTC), a.Updated)
+}
+
+func TestInCrowdProvider_List(t *testing.T) {
+	srv := serve(t, inCrowdListBody)
+	p := NewInCrowd(zap.NewNop(), config.Source{Team: "t1", URL: srv.URL}, options(srv))
+
+	items, err := p.List(context.Background())
+	if !assert.NoError(t, err) || !assert.Len(t, items, 3) {
+		return
+	}
+
+	// missing and broken update dates fall back to published
+	published := time.Date(2024, 2, 28, 9, 58, 47, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 2, 28, 10, 3, 13, 0, time.UTC), items[0].Article.Updated)
+	assert.Equal(t, published, items[1].Article.Updated)
+	assert.Equal(t, published, items[2].Article.Updated)
+	assert.True(t, items[2].IsPublished)
+}
+
+func TestInCrowdProvider_Article(t *testing.T) {
+	srv := serve(t, inCrowdArticleBody)
+	p := NewInCrowd(zap.NewNop(), config.Source{Team: "t1", URL: srv.URL}, options(srv))
+
+	a, err := p.Article(context.Background(), Item{Article: entity.Article{TeamID: "t1", ExternalId: 1}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "Title", a.Title)
+	assert.Equal(t, "Subtitle", a.Subtitle)
+	assert.Equal(t, "Brentford", a.ClubName)
+	assert.Equal(t, "https://www.brentfordfc.com", a.ClubWebsiteURL)
+	assert.Equal(t, "<p>Body</p>", a.Content)
+	assert.Equal(t, entity.MediaList{{URL: "https://www.brentfordfc.com/g/1.jpg", Kind: entity.MediaImage}}, a.GalleryUrls)
+	assert.Equal(t, time.Date(2024, 2, 28, 10, 3, 13, 0, time.UTC), a.Updated)
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"", InCrowd, RSS, Atom} {
+		p, err := New(zap.NewNop(), config.Source{Provider: name}, Options{})
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+	}
+
+	_, err := New(zap.NewNop(), config.Source{Provider: "json"}, Options{})
+	assert.Error(t, err)
+}
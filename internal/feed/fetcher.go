@@ -0,0 +1,263 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	cloudflarebp "github.com/DaRealFreak/cloudflare-bp-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/metrics"
+	"go.sport-news/internal/tracing"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fetcher does the feed requests with one long-lived client, so connections are reused.
+// It's shared by the providers of all sources.
+type Fetcher struct {
+	logger    *zap.Logger
+	client    *http.Client
+	limiter   *Limiter
+	userAgent string
+
+	mu         sync.Mutex
+	validators map[string]validator
+	stats      map[string]*ConditionalStats
+}
+
+// NewFetcher return fetcher with the client, tests pass the client of httptest.Server.
+// Nil limiter doesn't limit, empty userAgent keeps the client one.
+func NewFetcher(logger *zap.Logger, client *http.Client, limiter *Limiter, userAgent string) *Fetcher {
+	return &Fetcher{
+		logger:     logger,
+		client:     client,
+		limiter:    limiter,
+		userAgent:  userAgent,
+		validators: make(map[string]validator),
+		stats:      make(map[string]*ConditionalStats),
+	}
+}
+
+// MustLoad return fetcher with the tuned client and the rate limiter from config.
+func MustLoad(logger *zap.Logger, cfg config.Parser) *Fetcher {
+	client, err := NewClient(cfg)
+	if err != nil {
+		logger.Fatal("failed init feed client", zap.Error(err))
+	}
+
+	return NewFetcher(logger, client, NewLimiter(cfg.RateLimit, cfg.RateBurst), cfg.UserAgent)
+}
+
+// NewClient return http client with pooled keep-alive connections, HTTP/2 and gzip.
+// Proxy is taken from config or HTTP_PROXY envs, Cloudflare bypass is optional.
+func NewClient(cfg config.Parser) (*http.Client, error) {
+	proxy := http.ProxyFromEnvironment
+	if cfg.Proxy != "" {
+		u, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		// the bypass sets own TLS config, which turns off HTTP/2 unless forced
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		// gzip is asked for and decoded by the transport
+		DisableCompression: false,
+	}
+	if cfg.CloudflareBypass != 0 {
+		opts := cloudflarebp.GetDefaultOptions()
+		if cfg.UserAgent != "" {
+			opts.Headers["User-Agent"] = cfg.UserAgent
+		}
+		transport = cloudflarebp.AddCloudFlareByPass(transport, opts)
+	}
+
+	return &http.Client{Timeout: cfg.RequestTimeout, Transport: transport}, nil
+}
+
+// errStatus error of the response status.
+type errStatus struct {
+	code int
+	body []byte
+}
+
+func (e *errStatus) Error() string {
+	return fmt.Sprintf("err[%d],req:\n%s", e.code, e.body)
+}
+
+// Get do a http get request and decode xml response to data,
+// failed attempts are retried by the policy and every attempt is logged.
+// Requests wait for the host rate limit, on 429 and 503 the host is paused for Retry-After.
+func (f *Fetcher) Get(ctx context.Context, policy RetryPolicy, url string, data any) error {
+	return f.get(ctx, policy, url, data, false)
+}
+
+func (f *Fetcher) get(ctx context.Context, policy RetryPolicy, url string, data any, conditional bool) error {
+	var (
+		te      error
+		started = time.Now()
+	)
+
+	for attempt := 1; ; attempt++ {
+		if err := f.limiter.Wait(ctx, url); err != nil {
+			return multierr.Append(te, err)
+		}
+
+		begin := time.Now()
+		status, wait, err := f.attempt(ctx, url, data, conditional, attempt)
+		metrics.FeedRequest(hostOf(url), status, time.Since(begin))
+		fields := append([]zap.Field{
+			zap.String("url", url),
+			zap.Int("attempt", attempt),
+			zap.Int("status", status),
+			zap.Duration("duration", time.Since(begin)),
+		}, tracing.Fields(ctx)...)
+		if err == nil || errors.Is(err, ErrNotModified) {
+			f.logger.Debug("request done", fields...)
+			return err
+		}
+		te = multierr.Append(te, err)
+
+		var es *errStatus
+		retryable := ctx.Err() == nil && (!errors.As(err, &es) || policy.Retryable(es.code))
+		if status == http.StatusOK {
+			// the body can't be decoded, another attempt gets the same body
+			retryable = false
+		}
+
+		// all requests to the host wait, not only this one
+		f.limiter.Pause(url, wait)
+
+		delay := policy.Delay(attempt)
+		if wait > delay {
+			delay = wait
+		}
+		if !retryable || !policy.next(attempt, started, delay) {
+			f.logger.Warn("request failed", append(fields, zap.Error(err))...)
+			return te
+		}
+		f.logger.Warn("request failed, retry", append(fields, zap.Error(err), zap.Duration("delay", delay))...)
+
+		if wait > 0 && f.limiter != nil {
+			// the paused limiter delays the next attempt
+			f.limiter.Pause(url, delay)
+			continue
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return multierr.Append(te, ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// attempt do the request in its own span.
+func (f *Fetcher) attempt(ctx context.Context, url string, data any, conditional bool, attempt int) (int, time.Duration, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"feed.request",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", http.MethodGet),
+			attribute.String("url.full", url),
+			attribute.String("server.address", hostOf(url)),
+			attribute.Int("http.request.resend_count", attempt-1),
+		),
+	)
+
+	status, wait, err := f.do(ctx, url, data, conditional)
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if errors.Is(err, ErrNotModified) {
+		tracing.End(span, nil)
+	} else {
+		tracing.End(span, err)
+	}
+
+	return status, wait, err
+}
+
+// do one attempt, returns response status
+// and how long the host asked to wait with Retry-After.
+func (f *Fetcher) do(ctx context.Context, url string, data any, conditional bool) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, bytes.NewBuffer(nil))
+	if err != nil {
+		return 0, 0, err
+	}
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+	if conditional {
+		f.condition(req, url)
+	}
+
+	response, err := f.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if conditional && response.StatusCode == http.StatusNotModified {
+		s := f.hit(url)
+		f.logger.Debug("not modified", zap.String("url", url), zap.Int64("hits", s.Hits), zap.Int64("misses", s.Misses))
+		return response.StatusCode, 0, ErrNotModified
+	}
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(response.Body)
+		var wait time.Duration
+		if response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable {
+			wait = retryAfter(response.Header.Get("Retry-After"), time.Now())
+		}
+
+		return response.StatusCode, wait, &errStatus{code: response.StatusCode, body: body}
+	}
+
+	if err := xml.NewDecoder(response.Body).Decode(&data); err != nil {
+		return response.StatusCode, 0, fmt.Errorf("failed decode response in %s, %w", url, err)
+	}
+	if conditional {
+		f.remember(url, response.Header)
+	}
+
+	return response.StatusCode, 0, nil
+}
+
+// retryAfter parse Retry-After header, delay in seconds or http date.
+func retryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(now)
+	}
+
+	return 0
+}
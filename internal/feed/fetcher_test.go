@@ -0,0 +1,95 @@
+package feed
+
+import (
+	"compress/gzip"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.sport-news/internal/config"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetcher_Get(t *testing.T) {
+	var (
+		conns     atomic.Int32
+		userAgent atomic.Value
+	)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent.Store(r.Header.Get("User-Agent"))
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`<NewListInformation><ClubName>Club</ClubName></NewListInformation>`))
+		_ = gz.Close()
+	}))
+	srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	client, err := NewClient(config.Parser{CloudflareBypass: 1, UserAgent: "sport-news/1.0"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	f := NewFetcher(zap.NewNop(), client, nil, "sport-news/1.0")
+
+	for range 3 {
+		var data News
+		assert.NoError(t, f.Get(context.Background(), RetryPolicy{Attempts: 1}, srv.URL, &data))
+		assert.Equal(t, "Club", data.ClubName)
+	}
+	assert.Equal(t, int32(1), conns.Load(), "connection is reused")
+	assert.Equal(t, "sport-news/1.0", userAgent.Load())
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(config.Parser{Proxy: "http://proxy.test:3128"})
+	assert.NoError(t, err)
+
+	_, err = NewClient(config.Parser{Proxy: "://proxy"})
+	assert.Error(t, err)
+}
+
+func TestFetcher_GetSpans(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`<NewListInformation><ClubName>Club</ClubName></NewListInformation>`))
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(zap.NewNop(), srv.Client(), nil, "")
+	var data News
+	assert.NoError(t, f.Get(context.Background(), RetryPolicy{Attempts: 2, Statuses: DefaultRetryStatuses}, srv.URL, &data))
+
+	// one span per attempt
+	spans := sr.Ended()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, "feed.request", spans[0].Name())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	}
+}
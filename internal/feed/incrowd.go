@@ -0,0 +1,186 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type NewsItem struct {
+	ArticleURL        string `xml:"ArticleURL"`
+	NewsArticleID     int    `xml:"NewsArticleID"`
+	PublishDate       string `xml:"PublishDate"`
+	Taxonomies        string `xml:"Taxonomies"`
+	TeaserText        string `xml:"TeaserText"`
+	ThumbnailImageURL string `xml:"ThumbnailImageURL"`
+	Title             string `xml:"Title"`
+	OptaMatchId       string `xml:"OptaMatchId"`
+	LastUpdateDate    string `xml:"LastUpdateDate"`
+	IsPublished       string `xml:"IsPublished"`
+}
+type News struct {
+	XMLName             xml.Name `xml:"NewListInformation"`
+	ClubName            string   `xml:"ClubName"`
+	ClubWebsiteURL      string   `xml:"ClubWebsiteURL"`
+	NewsletterNewsItems struct {
+		NewsletterNewsItem []NewsItem `xml:"NewsletterNewsItem"`
+	} `xml:"NewsletterNewsItems"`
+}
+type NewsArticleInformation struct {
+	XMLName        xml.Name `xml:"NewsArticleInformation"`
+	ClubName       string   `xml:"ClubName"`
+	ClubWebsiteURL string   `xml:"ClubWebsiteURL"`
+	NewsArticle    struct {
+		ArticleURL        string `xml:"ArticleURL"`
+		NewsArticleID     int    `xml:"NewsArticleID"`
+		PublishDate       string `xml:"PublishDate"`
+		Taxonomies        string `xml:"Taxonomies"`
+		TeaserText        string `xml:"TeaserText"`
+		Subtitle          string `xml:"Subtitle"`
+		ThumbnailImageURL string `xml:"ThumbnailImageURL"`
+		Title             string `xml:"Title"`
+		BodyText          string `xml:"BodyText"`
+		GalleryImageURLs  string `xml:"GalleryImageURLs"`
+		VideoURL          string `xml:"VideoURL"`
+		OptaMatchId       string `xml:"OptaMatchId"`
+		LastUpdateDate    string `xml:"LastUpdateDate"`
+		IsPublished       string `xml:"IsPublished"`
+	} `xml:"NewsArticle"`
+}
+
+// InCrowdProvider reads the InCrowd XML feed,
+// getnewlistinformation for the list and getnewsarticleinformation for details.
+type InCrowdProvider struct {
+	logger *zap.Logger
+	src    config.Source
+	opts   Options
+}
+
+func NewInCrowd(logger *zap.Logger, src config.Source, opts Options) *InCrowdProvider {
+	return &InCrowdProvider{logger: logger, src: src, opts: opts}
+}
+
+// List return the latest articles from getnewlistinformation.
+func (p *InCrowdProvider) List(ctx context.Context) ([]Item, error) {
+	var (
+		a   News
+		url = p.listURL()
+	)
+	if err := p.opts.Fetcher.GetConditional(ctx, p.opts.ListRetry, url, &a); err != nil {
+		return nil, fmt.Errorf("failed do request %s: %w", url, err)
+	}
+
+	items := make([]Item, 0, len(a.NewsletterNewsItems.NewsletterNewsItem))
+	for _, item := range a.NewsletterNewsItems.NewsletterNewsItem {
+		published, err := time.Parse(time.DateTime, item.PublishDate)
+		if err != nil {
+			p.logger.Error("failed parse date", zap.Error(err), zap.String("data", item.PublishDate))
+			continue
+		}
+		// the item is kept without the update date, it's published then
+		updated := published
+		if item.LastUpdateDate != "" {
+			u, err := time.Parse(time.DateTime, item.LastUpdateDate)
+			if err != nil {
+				p.logger.Warn("failed parse update date", zap.Error(err), zap.String("data", item.LastUpdateDate))
+			} else {
+				updated = u
+			}
+		}
+
+		categories := entity.ParseCategories(item.Taxonomies)
+		items = append(items, Item{
+			Article: entity.Article{
+				TeamID:         p.src.Team,
+				ExternalId:     item.NewsArticleID,
+				OptaMatchID:    optional(item.OptaMatchId),
+				Title:          item.Title,
+				Type:           entity.CategoryNames(categories),
+				Categories:     categories,
+				Teaser:         item.TeaserText,
+				URL:            item.ArticleURL,
+				ImageURL:       item.ThumbnailImageURL,
+				Published:      published,
+				Updated:        updated,
+				ClubName:       a.ClubName,
+				ClubWebsiteURL: a.ClubWebsiteURL,
+			},
+			IsPublished: isPublished(item.IsPublished),
+		})
+	}
+
+	return items, nil
+}
+
+// Forget the last list response.
+func (p *InCrowdProvider) Forget() {
+	p.opts.Fetcher.Forget(p.listURL())
+}
+
+func (p *InCrowdProvider) listURL() string {
+	return fmt.Sprintf("%s/getnewlistinformation?count=%d", p.src.URL, p.src.Count)
+}
+
+// Article return the full article from getnewsarticleinformation.
+func (p *InCrowdProvider) Article(ctx context.Context, item Item) (entity.Article, error) {
+	var (
+		aI  NewsArticleInformation
+		url = fmt.Sprintf("%s/getnewsarticleinformation?id=%d", p.src.URL, item.Article.ExternalId)
+	)
+	if err := p.opts.Fetcher.Get(ctx, p.opts.ArticleRetry, url, &aI); err != nil {
+		return entity.Article{}, fmt.Errorf("failed do request %s: %w", url, err)
+	}
+
+	n := aI.NewsArticle
+	if !isPublished(n.IsPublished) {
+		return entity.Article{}, ErrUnpublished
+	}
+
+	// details are fresher than the list item
+	a := item.Article
+	a.Title = n.Title
+	a.Subtitle = n.Subtitle
+	a.Categories = entity.ParseCategories(n.Taxonomies)
+	a.Type = entity.CategoryNames(a.Categories)
+	a.Teaser = n.TeaserText
+	a.Content = n.BodyText
+	a.URL = n.ArticleURL
+	a.ImageURL = n.ThumbnailImageURL
+	a.GalleryUrls = entity.ParseMedia(n.GalleryImageURLs, entity.MediaImage, aI.ClubWebsiteURL)
+	a.VideoURL = entity.ParseMedia(n.VideoURL, entity.MediaVideo, aI.ClubWebsiteURL)
+	a.OptaMatchID = optional(n.OptaMatchId)
+	if aI.ClubName != "" {
+		a.ClubName = aI.ClubName
+	}
+	if aI.ClubWebsiteURL != "" {
+		a.ClubWebsiteURL = aI.ClubWebsiteURL
+	}
+
+	if n.LastUpdateDate != "" {
+		u, err := time.Parse(time.DateTime, n.LastUpdateDate)
+		if err != nil {
+			return entity.Article{}, fmt.Errorf("failed parse date %q: %w", n.LastUpdateDate, err)
+		}
+		a.Updated = u
+	}
+
+	return a, nil
+}
+
+// isPublished parse IsPublished feed flag, empty value means published.
+func isPublished(v string) bool {
+	return !strings.EqualFold(strings.TrimSpace(v), "false")
+}
+
+func optional(v string) *string {
+	if v == "" {
+		return nil
+	}
+
+	return &v
+}
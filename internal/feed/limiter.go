@@ -0,0 +1,119 @@
+package feed
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Limiter token bucket rate limiter per host, shared by the providers
+// so all requests to one host are limited together. Nil Limiter doesn't limit.
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   int
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// until host asked to wait with Retry-After.
+	until time.Time
+}
+
+// NewLimiter return limiter of rate requests per second with burst, rate <= 0 disables limit.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{rate: rate, burst: burst, buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Wait block until a request to the host of link is allowed.
+func (l *Limiter) Wait(ctx context.Context, link string) error {
+	if l == nil {
+		return nil
+	}
+	host := hostOf(link)
+
+	for {
+		d := l.reserve(host)
+		if d <= 0 {
+			return nil
+		}
+
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Pause stop requests to the host of link for d, used for Retry-After.
+func (l *Limiter) Pause(link string, d time.Duration) {
+	if l == nil || d <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(hostOf(link))
+	if until := l.now().Add(d); until.After(b.until) {
+		b.until = until
+	}
+}
+
+// reserve take a token of the host, returns how long to wait when there is none.
+func (l *Limiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.bucket(host)
+	if now.Before(b.until) {
+		return b.until.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > float64(l.burst) {
+		b.tokens = float64(l.burst)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *Limiter) bucket(host string) *bucket {
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: l.now()}
+		l.buckets[host] = b
+	}
+
+	return b
+}
+
+func hostOf(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	return u.Host
+}
@@ -0,0 +1,67 @@
+package feed
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimiter_reserve(t *testing.T) {
+	now := time.Date(2024, 2, 28, 10, 0, 0, 0, time.UTC)
+	l := NewLimiter(2, 2)
+	l.now = func() time.Time { return now }
+
+	assert.Zero(t, l.reserve("a.test"))
+	assert.Zero(t, l.reserve("a.test"))
+	assert.Equal(t, 500*time.Millisecond, l.reserve("a.test"), "burst is spent")
+	assert.Zero(t, l.reserve("b.test"), "hosts have own buckets")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Zero(t, l.reserve("a.test"))
+
+	l.Pause("https://a.test/api", 10*time.Second)
+	assert.Equal(t, 10*time.Second, l.reserve("a.test"))
+	assert.Zero(t, l.reserve("b.test"))
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	var l *Limiter
+	assert.NoError(t, l.Wait(context.Background(), "https://a.test"), "nil limiter doesn't limit")
+
+	l = NewLimiter(0, 0)
+	assert.NoError(t, l.Wait(context.Background(), "https://a.test"), "zero rate doesn't limit")
+
+	l.Pause("https://a.test", time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.Wait(ctx, "https://a.test"), context.Canceled)
+}
+
+func TestFetcher_Get_RetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	l := NewLimiter(0, 0)
+	var data News
+	assert.Error(t, NewFetcher(zap.NewNop(), srv.Client(), l, "").Get(context.Background(), RetryPolicy{Attempts: 1}, srv.URL, &data))
+
+	d := l.reserve(hostOf(srv.URL))
+	assert.Greater(t, d, 110*time.Second)
+	assert.LessOrEqual(t, d, 120*time.Second)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 2, 28, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 30*time.Second, retryAfter("30", now))
+	assert.Equal(t, time.Minute, retryAfter("Wed, 28 Feb 2024 10:01:00 GMT", now))
+	assert.Zero(t, retryAfter("", now))
+	assert.Zero(t, retryAfter("soon", now))
+}
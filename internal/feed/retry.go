@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"go.sport-news/internal/config"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryStatuses statuses worth another attempt.
+//
+//nolint:gochecknoglobals
+var DefaultRetryStatuses = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy how a failed request is retried: exponential backoff from BaseDelay
+// up to MaxDelay, every delay is shortened by a random part of Jitter,
+// no retries start after MaxElapsed since the first attempt.
+type RetryPolicy struct {
+	// Attempts max attempts including the first one.
+	Attempts   int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	MaxElapsed time.Duration
+	// Jitter part of the delay from 0 to 1 which is random.
+	Jitter   float64
+	Statuses []int
+}
+
+// NewRetryPolicy make policy of the endpoint from config with DefaultRetryStatuses.
+func NewRetryPolicy(cfg config.Retry) RetryPolicy {
+	return RetryPolicy{
+		Attempts:   cfg.Attempts,
+		BaseDelay:  cfg.BaseDelay,
+		MaxDelay:   cfg.MaxDelay,
+		MaxElapsed: cfg.MaxElapsed,
+		Jitter:     0.5,
+		Statuses:   DefaultRetryStatuses,
+	}
+}
+
+// Retryable report the response status is worth another attempt.
+func (p RetryPolicy) Retryable(status int) bool {
+	for _, s := range p.Statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delay before the attempt after the failed one, attempt starts from 1.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d)) //nolint:gosec
+	}
+
+	return d
+}
+
+// next report whether another attempt fits the policy after the failed attempt,
+// started is the time of the first attempt.
+func (p RetryPolicy) next(attempt int, started time.Time, delay time.Duration) bool {
+	if attempt >= p.Attempts {
+		return false
+	}
+
+	return p.MaxElapsed <= 0 || time.Since(started)+delay <= p.MaxElapsed
+}
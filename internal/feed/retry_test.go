@@ -0,0 +1,85 @@
+package feed
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.Delay(2))
+	assert.Equal(t, 800*time.Millisecond, p.Delay(4))
+	assert.Equal(t, time.Second, p.Delay(5))
+	assert.Equal(t, time.Second, p.Delay(60))
+
+	p.Jitter = 0.5
+	for range 100 {
+		d := p.Delay(2)
+		assert.GreaterOrEqual(t, d, 100*time.Millisecond)
+		assert.LessOrEqual(t, d, 200*time.Millisecond)
+	}
+}
+
+func TestFetcher_Get_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		policy       RetryPolicy
+		wantErr      bool
+		wantAttempts int32
+	}{
+		{
+			name:         "retryable statuses",
+			statuses:     []int{http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusOK},
+			policy:       RetryPolicy{Attempts: 3, Statuses: DefaultRetryStatuses},
+			wantAttempts: 3,
+		},
+		{
+			name:         "not retryable status",
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			policy:       RetryPolicy{Attempts: 3, Statuses: DefaultRetryStatuses},
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+		{
+			name:         "attempts are over",
+			statuses:     []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			policy:       RetryPolicy{Attempts: 2, Statuses: DefaultRetryStatuses},
+			wantErr:      true,
+			wantAttempts: 2,
+		},
+		{
+			name:         "max elapsed",
+			statuses:     []int{http.StatusInternalServerError, http.StatusOK},
+			policy:       RetryPolicy{Attempts: 3, BaseDelay: time.Minute, MaxElapsed: time.Second, Statuses: DefaultRetryStatuses},
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[attempts.Add(1)-1])
+				_, _ = w.Write([]byte(`<NewListInformation><ClubName>Club</ClubName></NewListInformation>`))
+			}))
+			defer srv.Close()
+
+			var data News
+			err := NewFetcher(zap.NewNop(), srv.Client(), nil, "").Get(context.Background(), tt.policy, srv.URL, &data)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+			if !tt.wantErr {
+				assert.Equal(t, "Club", data.ClubName)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel struct {
+		Title string    `xml:"title"`
+		Link  string    `xml:"link"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	GUID        string   `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Enclosures  []struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+// rssDateLayouts layouts seen in pubDate, RFC 822 with variations.
+var rssDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+}
+
+// RSSProvider reads plain RSS 2.0 feed, the list carries full articles.
+type RSSProvider struct {
+	logger *zap.Logger
+	src    config.Source
+	opts   Options
+}
+
+func NewRSS(logger *zap.Logger, src config.Source, opts Options) *RSSProvider {
+	return &RSSProvider{logger: logger, src: src, opts: opts}
+}
+
+// List return the latest articles of the channel.
+func (p *RSSProvider) List(ctx context.Context) ([]Item, error) {
+	var f rssFeed
+	if err := p.opts.Fetcher.GetConditional(ctx, p.opts.ListRetry, p.src.URL, &f); err != nil {
+		return nil, fmt.Errorf("failed do request %s: %w", p.src.URL, err)
+	}
+
+	items := make([]Item, 0, len(f.Channel.Items))
+	for _, item := range f.Channel.Items {
+		if p.src.Count > 0 && len(items) == p.src.Count {
+			break
+		}
+
+		published, err := parseDate(rssDateLayouts, item.PubDate)
+		if err != nil {
+			p.logger.Error("failed parse date", zap.Error(err), zap.String("data", item.PubDate))
+			continue
+		}
+
+		id := item.GUID
+		if id == "" {
+			id = item.Link
+		}
+
+		a := entity.Article{
+			TeamID:     p.src.Team,
+			ExternalId: externalID(id),
+			Title:      strings.TrimSpace(item.Title),
+			Categories: entity.ParseCategories(item.Categories...),
+			Teaser:     item.Description,
+			Content:    item.Content,
+			URL:        item.Link,
+			Published:  published,
+			// RSS has no edit date, so edits are not tracked
+			Updated:        published,
+			ClubName:       strings.TrimSpace(f.Channel.Title),
+			ClubWebsiteURL: f.Channel.Link,
+		}
+		a.Type = entity.CategoryNames(a.Categories)
+		if a.Content == "" {
+			a.Content = item.Description
+		}
+		for _, e := range item.Enclosures {
+			switch {
+			case strings.HasPrefix(e.Type, "image/"):
+				a.GalleryUrls = append(a.GalleryUrls, entity.NewMedia(e.URL, entity.MediaImage))
+			case strings.HasPrefix(e.Type, "video/"):
+				a.VideoURL = append(a.VideoURL, entity.NewMedia(e.URL, entity.MediaVideo))
+			}
+		}
+		if len(a.GalleryUrls) > 0 {
+			a.ImageURL = a.GalleryUrls[0].URL
+		}
+
+		items = append(items, Item{Article: a, IsPublished: true})
+	}
+
+	return items, nil
+}
+
+// Forget the last channel response.
+func (p *RSSProvider) Forget() {
+	p.opts.Fetcher.Forget(p.src.URL)
+}
+
+// Article return the article from the list, RSS has no details.
+func (p *RSSProvider) Article(_ context.Context, item Item) (entity.Article, error) {
+	return item.Article, nil
+}
+
+func parseDate(layouts []string, v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	for _, l := range layouts {
+		if t, err := time.Parse(l, v); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown date format %q", v)
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.sport-news/internal/metrics"
+	"go.sport-news/internal/requestid"
+	"go.sport-news/internal/tracing"
+	"go.uber.org/zap"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// Bodies of the errors written by the middlewares, the same jsend envelope as handlers write.
+const (
+	internalErrorBody = `{"status":"error","message":"internal server error"}`
+	timeoutBody       = `{"status":"error","message":"request timeout"}`
+)
+
+//nolint:gochecknoglobals
+var requestIDRe = regexp.MustCompile(`^[\w.:-]{1,128}$`)
+
+// statusWriter remember status and size of the response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+// routeTemplate of the matched route, so ids don't make a label per article.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if t, err := route.GetPathTemplate(); err == nil {
+			return t
+		}
+	}
+
+	return "unknown"
+}
+
+// measure observe duration and status of the requests.
+func measure(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+
+		next.ServeHTTP(sw, r)
+
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		metrics.Request(routeTemplate(r), r.Method, sw.status, time.Since(started))
+	})
+}
+
+// traced start server span of the request, the trace of the caller is continued.
+// Handlers get the span in r.Context().
+func traced(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeTemplate(r)
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(
+			ctx,
+			r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("http.request_id", requestid.IDFromCtx(r.Context())),
+			),
+		)
+		defer span.End()
+
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", sw.status))
+		if sw.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(sw.status))
+		}
+	})
+}
+
+// requestID take X-Request-ID of the request or make a new one,
+// it's set to the response and the request context.
+func requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestIDRe.MatchString(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestid.Header, id)
+
+		next.ServeHTTP(w, r.WithContext(requestid.CtxWithID(r.Context(), id)))
+	})
+}
+
+// probes routes of the probes and scrapes, they are logged at debug unless they fail.
+var probes = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// accessLog write a log line per request.
+func (s *Server) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+
+		next.ServeHTTP(sw, r)
+
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		route := routeTemplate(r)
+		level := zap.InfoLevel
+		if probes[route] && sw.status < http.StatusInternalServerError {
+			level = zap.DebugLevel
+		}
+		s.log(r).Log(
+			level,
+			"http request",
+			zap.String("method", r.Method),
+			zap.String("route", route),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", sw.status),
+			zap.Int("bytes", sw.bytes),
+			zap.Duration("duration", time.Since(started)),
+		)
+	})
+}
+
+// recovery log panic of the handler and respond with internal error,
+// when the handler hasn't written the response yet.
+func (s *Server) recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler { //nolint:errorlint
+				// the client is gone, net/http handles it
+				panic(p)
+			}
+
+			s.log(r).Error(
+				"handler panic",
+				zap.String("route", routeTemplate(r)),
+				zap.Any("panic", p),
+				zap.Stack("stack"),
+			)
+			if sw.status == 0 {
+				sw.Header().Set("Content-Type", "application/json")
+				sw.WriteHeader(http.StatusInternalServerError)
+				_, _ = sw.Write([]byte(internalErrorBody))
+			}
+		}()
+
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// use add the middlewares to the routes and to the not found and method not allowed answers,
+// mux runs router middlewares only for matched routes.
+func use(r *mux.Router, mw ...mux.MiddlewareFunc) {
+	r.Use(mw...)
+
+	var notFound, notAllowed http.Handler = http.NotFoundHandler(), http.HandlerFunc(methodNotAllowed)
+	for i := len(mw) - 1; i >= 0; i-- {
+		notFound, notAllowed = mw[i](notFound), mw[i](notAllowed)
+	}
+	r.NotFoundHandler, r.MethodNotAllowedHandler = notFound, notAllowed
+}
+
+// methodNotAllowed answer like mux does without MethodNotAllowedHandler.
+func methodNotAllowed(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusMethodNotAllowed)
+}
+
+// log return logger with request id and trace ids of the request.
+func (s *Server) log(r *http.Request) *zap.Logger {
+	return tracing.Logger(r.Context(), s.logger).With(zap.String("requestId", requestid.IDFromCtx(r.Context())))
+}
+
+// handle register the handler, it gets 503 when it doesn't respond in d, 0 means no timeout.
+// The request context is done after d, so db queries of the handler are stopped too.
+func handle(r *mux.Router, path string, h http.HandlerFunc, d time.Duration) *mux.Route {
+	if d <= 0 {
+		return r.Handle(path, h)
+	}
+
+	th := http.TimeoutHandler(h, d, timeoutBody)
+
+	return r.Handle(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the timeout response keeps it, handler responses set their own
+		w.Header().Set("Content-Type", "application/json")
+		th.ServeHTTP(w, r)
+	}))
+}
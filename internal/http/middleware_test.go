@@ -0,0 +1,207 @@
+package http
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.sport-news/internal/metrics"
+	"go.sport-news/internal/requestid"
+	"go.sport-news/internal/tracing"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMeasure(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(measure)
+	r.HandleFunc("/v1/teams/{team}/news/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	for _, id := range []string{"1", "2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/teams/t94/news/"+id, nil))
+	}
+
+	// one series per route template, not per article
+	count, err := testutil.GatherAndCount(metrics.Registry(), "sport_news_http_request_duration_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(
+		t,
+		w.Body.String(),
+		`sport_news_http_request_duration_seconds_count{method="GET",route="/v1/teams/{team}/news/{id}",status="404"} 2`,
+	)
+}
+
+func TestTraced(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var fields []zap.Field
+	r := mux.NewRouter()
+	r.Use(traced)
+	r.HandleFunc("/v1/teams/{team}/news", func(w http.ResponseWriter, r *http.Request) {
+		fields = tracing.Fields(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/teams/t94/news", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := sr.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "GET /v1/teams/{team}/news", spans[0].Name())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+	}
+	if assert.Len(t, fields, 2) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fields[0].String)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var got string
+	h := requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = requestid.IDFromCtx(r.Context())
+	}))
+
+	for header, keep := range map[string]bool{"abc-123": true, "": false, "bad id\n": false} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(requestid.Header, header)
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, got, w.Header().Get(requestid.Header))
+		if keep {
+			assert.Equal(t, header, got)
+		} else {
+			assert.NotEqual(t, header, got)
+			assert.NotEmpty(t, got)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	s := &Server{logger: zap.New(core)}
+
+	r := mux.NewRouter()
+	r.Use(requestID, s.accessLog, s.recovery)
+	handle(r, "/v1/teams/{team}/news", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("news"))
+	}, time.Second)
+	handle(r, "/v1/teams/{team}/categories", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}, time.Second)
+	handle(r, "/v1/teams/{team}/news/search", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}, 10*time.Millisecond)
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{path: "/v1/teams/t94/news", wantCode: http.StatusOK, wantBody: "news"},
+		{path: "/v1/teams/t94/categories", wantCode: http.StatusInternalServerError, wantBody: internalErrorBody},
+		{path: "/v1/teams/t94/news/search", wantCode: http.StatusServiceUnavailable, wantBody: timeoutBody},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			logs.TakeAll()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			assert.NotEmpty(t, w.Header().Get(requestid.Header))
+
+			access := logs.FilterMessage("http request").All()
+			if assert.Len(t, access, 1) {
+				fields := access[0].ContextMap()
+				assert.Equal(t, int64(tt.wantCode), fields["status"])
+				assert.Equal(t, int64(len(tt.wantBody)), fields["bytes"])
+				assert.Equal(t, w.Header().Get(requestid.Header), fields["requestId"])
+				assert.Contains(t, fields["route"], "/v1/teams/{team}/")
+			}
+			if tt.wantCode == http.StatusInternalServerError {
+				assert.Len(t, logs.FilterMessage("handler panic").All(), 1)
+			}
+		})
+	}
+}
+
+func TestUse_unmatched(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	s := &Server{logger: zap.New(core)}
+
+	r := mux.NewRouter()
+	use(r, requestID, s.accessLog, s.recovery)
+	handle(r, "/v1/teams/{team}/news", func(w http.ResponseWriter, r *http.Request) {}, time.Second).Methods("GET")
+	admin := r.PathPrefix("/v1/admin").Subrouter()
+	handle(admin, "/jobs", func(w http.ResponseWriter, r *http.Request) {}, time.Second).Methods("GET")
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+	}{
+		{method: http.MethodGet, path: "/v1/missing", wantCode: http.StatusNotFound},
+		{method: http.MethodGet, path: "/v1/admin/missing", wantCode: http.StatusNotFound},
+		{method: http.MethodPost, path: "/v1/teams/t94/news", wantCode: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: "/v1/admin/jobs", wantCode: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			logs.TakeAll()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.NotEmpty(t, w.Header().Get(requestid.Header))
+
+			access := logs.FilterMessage("http request").All()
+			if assert.Len(t, access, 1) {
+				fields := access[0].ContextMap()
+				assert.Equal(t, int64(tt.wantCode), fields["status"])
+				assert.Equal(t, "unknown", fields["route"])
+			}
+		})
+	}
+}
+
+func TestAccessLog_probes(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	s := &Server{logger: zap.New(core)}
+
+	ready := http.StatusOK
+	r := mux.NewRouter()
+	r.Use(s.accessLog)
+	handle(r, "/readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(ready)
+	}, time.Second)
+
+	// a passing probe is logged at debug, a failing one at info
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Empty(t, logs.FilterMessage("http request").All())
+
+	ready = http.StatusServiceUnavailable
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Len(t, logs.FilterMessage("http request").All(), 1)
+}
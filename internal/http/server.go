@@ -7,22 +7,33 @@ import (
 	"go.sport-news/internal/config"
 	v1 "go.sport-news/internal/controller/http/v1"
 	"go.sport-news/internal/environment"
+	"go.sport-news/internal/metrics"
 	"go.uber.org/zap"
 	"net/http"
 )
 
 type Server struct {
-	logger         *zap.Logger
-	config         *config.Http
-	newsController v1.INewsController
-	srv            *http.Server
+	logger           *zap.Logger
+	config           *config.Http
+	newsController   v1.INewsController
+	adminController  v1.IAdminController
+	healthController v1.IHealthController
+	srv              *http.Server
 }
 
-func New(nC v1.INewsController, log *zap.Logger, cfg *config.Http) *Server {
+func New(
+	nC v1.INewsController,
+	aC v1.IAdminController,
+	hC v1.IHealthController,
+	log *zap.Logger,
+	cfg *config.Http,
+) *Server {
 	return &Server{
-		logger:         log,
-		config:         cfg,
-		newsController: nC,
+		logger:           log,
+		config:           cfg,
+		newsController:   nC,
+		adminController:  aC,
+		healthController: hC,
 		srv: &http.Server{
 			Addr:         fmt.Sprintf(":%d", cfg.Port),
 			WriteTimeout: cfg.WriteTimeout,
@@ -36,12 +47,33 @@ func New(nC v1.INewsController, log *zap.Logger, cfg *config.Http) *Server {
 func (s *Server) Serve(ctx context.Context) error {
 
 	r := mux.NewRouter()
+	use(r, requestID, traced, measure, s.accessLog, s.recovery)
 
-	r.HandleFunc("/v1/teams/{team}/news", s.newsController.GetTeamNews).Methods("GET")
-	r.HandleFunc("/v1/teams/{team}/news/{id}", s.newsController.GetTeamNewsByID).Methods("GET")
+	ht, st := s.config.HandlerTimeout, s.config.SearchTimeout
+	handle(r, "/metrics", metrics.Handler().ServeHTTP, ht).Methods("GET")
+	handle(r, "/healthz", s.healthController.Live, ht).Methods("GET")
+	handle(r, "/readyz", s.healthController.Ready, ht).Methods("GET")
+	handle(r, "/v1/teams/{team}/news", s.newsController.GetTeamNews, ht).Methods("GET")
+	handle(r, "/v1/teams/{team}/news/search", s.newsController.SearchTeamNews, st).Methods("GET")
+	handle(r, "/v1/teams/{team}/news/{id}", s.newsController.GetTeamNewsByID, ht).Methods("GET")
+	handle(r, "/v1/teams/{team}/categories", s.newsController.GetTeamCategories, ht).Methods("GET")
 
 	if environment.EnvFromCtx(ctx).IsLocal() {
-		r.HandleFunc("/v1/cache-flush", s.newsController.ResetCache).Methods("POST")
+		handle(r, "/v1/cache-flush", s.newsController.ResetCache, ht).Methods("POST")
+	}
+
+	if s.config.AdminToken != "" {
+		admin := r.PathPrefix("/v1/admin").Subrouter()
+		admin.Use(s.adminController.Auth)
+		handle(admin, "/ingest/runs", s.adminController.GetIngestRuns, ht).Methods("GET")
+		handle(admin, "/ingest/runs/{id}", s.adminController.GetIngestRun, ht).Methods("GET")
+		handle(admin, "/jobs", s.adminController.GetJobs, ht).Methods("GET")
+		handle(admin, "/jobs/{team}", s.adminController.GetJob, ht).Methods("GET")
+		handle(admin, "/jobs/{team}/run", s.adminController.RunJob, ht).Methods("POST")
+		handle(admin, "/jobs/{team}/pause", s.adminController.PauseJob, ht).Methods("POST")
+		handle(admin, "/jobs/{team}/resume", s.adminController.ResumeJob, ht).Methods("POST")
+	} else {
+		s.logger.Info("admin endpoints are off, HTTP_ADMIN_TOKEN is empty")
 	}
 
 	s.srv.Handler = r
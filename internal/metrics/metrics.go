@@ -0,0 +1,161 @@
+// Package metrics keeps Prometheus metrics of the API, the news cache,
+// the ingest, the feed requests and Mongo operations.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.sport-news/internal/entity"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "sport_news"
+
+//nolint:gochecknoglobals
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of the API requests by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups by result, hit or miss.",
+	}, []string{"cache", "result"})
+	cacheItems = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_items",
+		Help:      "Items in the cache, expired ones are counted until cleanup.",
+	}, []string{"cache"})
+
+	ingestRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ingest_runs_total",
+		Help:      "Scheduler runs by source and status.",
+	}, []string{"team", "status"})
+	ingestItems = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ingest_items_total",
+		Help:      "Feed articles by source and kind: fetched, new, updated, upserted, retracted, failed.",
+	}, []string{"team", "kind"})
+	ingestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ingest_run_duration_seconds",
+		Help:      "Duration of the scheduler runs by source.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"team"})
+
+	feedRequests = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "feed_request_duration_seconds",
+		Help:      "Duration of the feed request attempts by host and status, status is error when there is no response.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 20},
+	}, []string{"host", "status"})
+	feedConditional = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feed_conditional_requests_total",
+		Help:      "Conditional feed list requests by host and result, hit is 304 and miss is a full download.",
+	}, []string{"host", "result"})
+
+	mongoOperations = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_operation_duration_seconds",
+		Help:      "Duration of Mongo operations by collection, operation and result.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"collection", "operation", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		cacheRequests,
+		cacheItems,
+		ingestRuns,
+		ingestItems,
+		ingestDuration,
+		feedRequests,
+		feedConditional,
+		mongoOperations,
+	)
+}
+
+// Handler serve metrics in Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// Registry return registry of the metrics, tests gather from it.
+func Registry() *prometheus.Registry {
+	return registry
+}
+
+// Request observe the API request.
+func Request(route, method string, status int, d time.Duration) {
+	httpRequests.WithLabelValues(route, method, strconv.Itoa(status)).Observe(d.Seconds())
+}
+
+// CacheLookup count the cache lookup.
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// CacheItems set count of the cache items, only the memory cache knows it.
+func CacheItems(cache string, items int) {
+	cacheItems.WithLabelValues(cache).Set(float64(items))
+}
+
+// IngestRun observe the finished scheduler run.
+func IngestRun(run entity.IngestRun) {
+	ingestRuns.WithLabelValues(run.TeamID, run.Status).Inc()
+	if run.FinishedAt != nil {
+		ingestDuration.WithLabelValues(run.TeamID).Observe(run.FinishedAt.Sub(run.StartedAt).Seconds())
+	}
+
+	for kind, n := range map[string]float64{
+		"fetched":   float64(run.Fetched),
+		"new":       float64(run.New),
+		"updated":   float64(run.Updated),
+		"upserted":  float64(run.Upserted),
+		"retracted": float64(run.Retracted),
+		"failed":    float64(run.Failed),
+	} {
+		ingestItems.WithLabelValues(run.TeamID, kind).Add(n)
+	}
+}
+
+// FeedRequest observe the feed request attempt, status 0 means there is no response.
+func FeedRequest(host string, status int, d time.Duration) {
+	s := "error"
+	if status != 0 {
+		s = strconv.Itoa(status)
+	}
+	feedRequests.WithLabelValues(host, s).Observe(d.Seconds())
+}
+
+// FeedConditional count the conditional feed request.
+func FeedConditional(host string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	feedConditional.WithLabelValues(host, result).Inc()
+}
+
+// MongoOperation observe the Mongo operation, result is ok, not_found or error.
+func MongoOperation(collection, operation, result string, d time.Duration) {
+	mongoOperations.WithLabelValues(collection, operation, result).Observe(d.Seconds())
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.sport-news/internal/entity"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIngestRun(t *testing.T) {
+	started := time.Now()
+	finished := started.Add(time.Second)
+
+	IngestRun(entity.IngestRun{TeamID: "t94", Status: entity.IngestSuccess, StartedAt: started, FinishedAt: &finished, Fetched: 3, Failed: 1})
+	IngestRun(entity.IngestRun{TeamID: "t94", Status: entity.IngestFailed, StartedAt: started, FinishedAt: &finished})
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(ingestRuns.WithLabelValues("t94", entity.IngestSuccess)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(ingestRuns.WithLabelValues("t94", entity.IngestFailed)))
+	assert.Equal(t, float64(3), testutil.ToFloat64(ingestItems.WithLabelValues("t94", "fetched")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(ingestItems.WithLabelValues("t94", "failed")))
+}
+
+func TestCacheLookup(t *testing.T) {
+	CacheLookup("test", true)
+	CacheLookup("test", false)
+	CacheLookup("test", false)
+	CacheItems("test", 3)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(cacheRequests.WithLabelValues("test", "hit")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(cacheRequests.WithLabelValues("test", "miss")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(cacheItems.WithLabelValues("test")))
+}
+
+func TestHandler(t *testing.T) {
+	FeedRequest("example.com", 0, time.Millisecond)
+	FeedConditional("example.com", true)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, `sport_news_feed_request_duration_seconds_count{host="example.com",status="error"} 1`))
+	assert.True(t, strings.Contains(body, `sport_news_feed_conditional_requests_total{host="example.com",result="hit"} 1`))
+}
@@ -0,0 +1,102 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/entity"
+	"go.sport-news/internal/feed"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ArticleDetails re-fetch details of the articles stored before subtitle,
+// club attribution and update time were kept. Only InCrowd has a details endpoint,
+// other feeds fill the fields on the next run of the scheduler.
+func ArticleDetails(ctx context.Context, logger *zap.Logger, db database.DB, cfg config.Parser) error {
+	sources, err := cfg.FeedSources()
+	if err != nil {
+		return err
+	}
+
+	client, err := feed.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	opts := feed.NewOptions(cfg, feed.NewFetcher(logger, client, feed.NewLimiter(cfg.RateLimit, cfg.RateBurst), cfg.UserAgent))
+	for _, src := range sources {
+		if src.Provider != feed.InCrowd && src.Provider != "" {
+			logger.Info("skip source without details", zap.String("team", src.Team), zap.String("provider", src.Provider))
+			continue
+		}
+
+		if err = articleDetails(ctx, logger.With(zap.String("team", src.Team)), db, src, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func articleDetails(ctx context.Context, logger *zap.Logger, db database.DB, src config.Source, opts feed.Options) error {
+	provider, err := feed.New(logger, src, opts)
+	if err != nil {
+		return err
+	}
+
+	var articles []entity.Article
+	if _, err = db.Find(
+		ctx,
+		bson.D{
+			{Key: "teamId", Value: src.Team},
+			{Key: "clubWebsiteUrl", Value: bson.D{{Key: "$exists", Value: false}}},
+			{Key: "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		},
+		nil,
+		&articles,
+	); err != nil {
+		return err
+	}
+
+	var migrated, failed int
+	for _, a := range articles {
+		reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		details, err := provider.Article(reqCtx, feed.Item{Article: a, IsPublished: true})
+		cancel()
+		switch {
+		case errors.Is(err, feed.ErrUnpublished):
+			// the scheduler retracts it when the list says so
+			logger.Info("article is unpublished", zap.Int("externalId", a.ExternalId))
+			continue
+		case err != nil:
+			logger.Error("failed get article details", zap.Error(err), zap.Int("externalId", a.ExternalId))
+			failed++
+			continue
+		}
+
+		if _, err = db.UpdateOne(
+			ctx,
+			bson.D{{Key: "id", Value: a.ID}},
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "subtitle", Value: details.Subtitle},
+				{Key: "clubName", Value: details.ClubName},
+				{Key: "clubWebsiteUrl", Value: details.ClubWebsiteURL},
+				{Key: "updated", Value: details.Updated},
+			}}},
+			nil,
+		); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	logger.Info("article details migrated",
+		zap.Int("total", len(articles)),
+		zap.Int("migrated", migrated),
+		zap.Int("failed", failed),
+	)
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ArticleIDs rewrite random article ids to entity.ArticleID
+// and record old to new mapping to database.ArticleIDMigrations.
+func ArticleIDs(ctx context.Context, logger *zap.Logger, db database.DB, _ config.Parser) error {
+	type ref struct {
+		ID         string `bson:"id"`
+		TeamID     string `bson:"teamId"`
+		ExternalId int    `bson:"externalId"`
+	}
+	var refs []ref
+
+	if _, err := db.Find(
+		ctx,
+		bson.D{},
+		&options.FindOptions{Projection: bson.D{
+			{Key: "id", Value: 1},
+			{Key: "teamId", Value: 1},
+			{Key: "externalId", Value: 1},
+		}},
+		&refs,
+	); err != nil {
+		return err
+	}
+
+	mapping := db.WithCollection(database.ArticleIDMigrations)
+	var migrated int
+	for _, r := range refs {
+		id := entity.ArticleID(r.TeamID, r.ExternalId)
+		if r.ID == id {
+			continue
+		}
+
+		// mapping goes first, so the old id is not lost when the run is interrupted
+		if _, err := mapping.UpdateOne(
+			ctx,
+			bson.D{{Key: "oldId", Value: r.ID}},
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "newId", Value: id},
+				{Key: "teamId", Value: r.TeamID},
+				{Key: "externalId", Value: r.ExternalId},
+				{Key: "migratedAt", Value: time.Now().UTC()},
+			}}},
+			options.Update().SetUpsert(true),
+		); err != nil {
+			return err
+		}
+
+		if _, err := db.UpdateOne(
+			ctx,
+			bson.D{
+				{Key: "teamId", Value: r.TeamID},
+				{Key: "externalId", Value: r.ExternalId},
+				{Key: "id", Value: r.ID},
+			},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "id", Value: id}}}},
+			nil,
+		); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	logger.Info("article ids migrated", zap.Int("total", len(refs)), zap.Int("migrated", migrated))
+
+	return nil
+}
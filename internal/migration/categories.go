@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+	"reflect"
+)
+
+// Categories split stored type values like "Club News, First Team"
+// to categories and distinct type names.
+func Categories(ctx context.Context, logger *zap.Logger, db database.DB, _ config.Parser) error {
+	type ref struct {
+		ID         string            `bson:"id"`
+		Type       []string          `bson:"type"`
+		Categories []entity.Category `bson:"categories"`
+	}
+	var refs []ref
+
+	if _, err := db.Find(
+		ctx,
+		bson.D{},
+		&options.FindOptions{Projection: bson.D{
+			{Key: "id", Value: 1},
+			{Key: "type", Value: 1},
+			{Key: "categories", Value: 1},
+		}},
+		&refs,
+	); err != nil {
+		return err
+	}
+
+	var migrated int
+	for _, r := range refs {
+		categories := entity.ParseCategories(r.Type...)
+		if reflect.DeepEqual(categories, r.Categories) {
+			continue
+		}
+
+		if _, err := db.UpdateOne(
+			ctx,
+			bson.D{{Key: "id", Value: r.ID}},
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "type", Value: entity.CategoryNames(categories)},
+				{Key: "categories", Value: categories},
+			}}},
+			nil,
+		); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	logger.Info("categories migrated", zap.Int("total", len(refs)), zap.Int("migrated", migrated))
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/content"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+)
+
+// Content sanitize stored raw article html and store its text and block renditions.
+func Content(ctx context.Context, logger *zap.Logger, db database.DB, _ config.Parser) error {
+	var articles []entity.Article
+	if _, err := db.Find(
+		ctx,
+		bson.D{{Key: "contentText", Value: bson.D{{Key: "$exists", Value: false}}}},
+		nil,
+		&articles,
+	); err != nil {
+		return err
+	}
+
+	for _, a := range articles {
+		content.Apply(&a)
+
+		update := bson.D{
+			{Key: "content", Value: a.Content},
+			{Key: "contentText", Value: a.ContentText},
+		}
+		if len(a.Blocks) > 0 {
+			update = append(update, bson.E{Key: "blocks", Value: a.Blocks})
+		}
+
+		if _, err := db.UpdateOne(ctx, bson.D{{Key: "id", Value: a.ID}}, bson.D{{Key: "$set", Value: update}}, nil); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("content migrated", zap.Int("migrated", len(articles)))
+
+	return nil
+}
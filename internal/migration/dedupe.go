@@ -0,0 +1,59 @@
+package migration
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.uber.org/zap"
+)
+
+// DedupeArticles remove duplicates of the team articles with the same external id
+// and create the unique indexes which failed on them.
+// Not retracted and the last updated duplicate is kept.
+func DedupeArticles(ctx context.Context, logger *zap.Logger, db database.DB, _ config.Parser) error {
+	type group struct {
+		Key struct {
+			TeamID     string `bson:"teamId"`
+			ExternalId int    `bson:"externalId"`
+		} `bson:"_id"`
+		IDs []primitive.ObjectID `bson:"ids"`
+	}
+	var groups []group
+
+	// missing deletedAt is sorted first, so the kept duplicate is the first one
+	if _, err := db.Aggregate(
+		ctx,
+		bson.A{
+			bson.D{{Key: "$sort", Value: bson.D{
+				{Key: "deletedAt", Value: 1},
+				{Key: "updated", Value: -1},
+				{Key: "lastSeen", Value: -1},
+			}}},
+			bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: bson.D{{Key: "teamId", Value: "$teamId"}, {Key: "externalId", Value: "$externalId"}}},
+				{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+			}}},
+			bson.D{{Key: "$match", Value: bson.D{{Key: "ids.1", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+		},
+		options.Aggregate().SetAllowDiskUse(true),
+		&groups,
+	); err != nil {
+		return err
+	}
+
+	var removed int64
+	for _, g := range groups {
+		n, err := db.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: g.IDs[1:]}}}}, nil)
+		if err != nil {
+			return err
+		}
+		removed += n
+	}
+
+	logger.Info("articles deduplicated", zap.Int("duplicated", len(groups)), zap.Int64("removed", removed))
+
+	return db.EnsureIndexes(ctx)
+}
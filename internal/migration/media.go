@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/entity"
+	"go.uber.org/zap"
+)
+
+// Media convert raw gallery and video strings stored from the feed to media lists.
+func Media(ctx context.Context, logger *zap.Logger, db database.DB, _ config.Parser) error {
+	type ref struct {
+		ID          string `bson:"id"`
+		URL         string `bson:"url"`
+		GalleryUrls any    `bson:"galleryUrls"`
+		VideoURL    any    `bson:"videoUrl"`
+	}
+	var refs []ref
+
+	if _, err := db.Find(
+		ctx,
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "galleryUrls", Value: bson.D{{Key: "$type", Value: "string"}}}},
+			bson.D{{Key: "videoUrl", Value: bson.D{{Key: "$type", Value: "string"}}}},
+		}}},
+		&options.FindOptions{Projection: bson.D{
+			{Key: "id", Value: 1},
+			{Key: "url", Value: 1},
+			{Key: "galleryUrls", Value: 1},
+			{Key: "videoUrl", Value: 1},
+		}},
+		&refs,
+	); err != nil {
+		return err
+	}
+
+	for _, r := range refs {
+		var set, unset bson.D
+		for _, f := range []struct {
+			key  string
+			raw  any
+			kind string
+		}{
+			{key: "galleryUrls", raw: r.GalleryUrls, kind: entity.MediaImage},
+			{key: "videoUrl", raw: r.VideoURL, kind: entity.MediaVideo},
+		} {
+			raw, ok := f.raw.(string)
+			if !ok {
+				continue
+			}
+			// relative urls are resolved against the article url
+			if m := entity.ParseMedia(raw, f.kind, r.URL); len(m) > 0 {
+				set = append(set, bson.E{Key: f.key, Value: m})
+			} else {
+				unset = append(unset, bson.E{Key: f.key, Value: ""})
+			}
+		}
+
+		var update bson.D
+		if len(set) > 0 {
+			update = append(update, bson.E{Key: "$set", Value: set})
+		}
+		if len(unset) > 0 {
+			update = append(update, bson.E{Key: "$unset", Value: unset})
+		}
+
+		if _, err := db.UpdateOne(ctx, bson.D{{Key: "id", Value: r.ID}}, update, nil); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("media migrated", zap.Int("migrated", len(refs)))
+
+	return nil
+}
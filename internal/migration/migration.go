@@ -0,0 +1,54 @@
+// Package migration one-off data migrations run by cmd/migrate.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.uber.org/zap"
+	"sort"
+)
+
+// Migration changes stored documents, it must be safe to run twice.
+// Parser config gives the feed sources to migrations which re-fetch articles.
+type Migration func(ctx context.Context, logger *zap.Logger, db database.DB, cfg config.Parser) error
+
+//nolint:gochecknoglobals
+var migrations = map[string]Migration{
+	"article-details": ArticleDetails,
+	"article-ids":     ArticleIDs,
+	"categories":      Categories,
+	"content":         Content,
+	"dedupe-articles": DedupeArticles,
+	"media":           Media,
+	"text-index":      TextIndex,
+}
+
+// Names return names of the known migrations.
+func Names() []string {
+	names := make([]string, 0, len(migrations))
+	for name := range migrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Run migration by name.
+func Run(ctx context.Context, logger *zap.Logger, db database.DB, cfg config.Parser, name string) error {
+	m, ok := migrations[name]
+	if !ok {
+		return fmt.Errorf("unknown migration %q", name)
+	}
+
+	logger = logger.With(zap.String("migration", name))
+	logger.Info("migration started")
+	if err := m(ctx, logger, db, cfg); err != nil {
+		return fmt.Errorf("migration %q: %w", name, err)
+	}
+	logger.Info("migration done")
+
+	return nil
+}
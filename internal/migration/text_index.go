@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"context"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.uber.org/zap"
+)
+
+// TextIndex recreate the article text index over the plain text of the content,
+// the old one over the html has the same name and conflicts with it.
+// Run the content migration first, so stored articles have the plain text.
+func TextIndex(ctx context.Context, logger *zap.Logger, db database.DB, _ config.Parser) error {
+	if err := db.DropIndex(ctx, database.ArticleTextIndex); err != nil {
+		return err
+	}
+	logger.Info("article text index dropped")
+
+	return db.EnsureIndexes(ctx)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/entity"
+)
+
+// ErrProgressNotFound returned when the backfill wasn't started yet.
+var ErrProgressNotFound = errors.New("backfill progress not found")
+
+//go:generate mockery --name BackfillRepository
+type BackfillRepository interface {
+	SaveProgress(ctx context.Context, p entity.BackfillProgress) error
+	GetProgress(ctx context.Context, id string) (*entity.BackfillProgress, error)
+}
+
+type Backfill struct {
+	db database.DB
+}
+
+func NewBackfillRepository(db database.DB) *Backfill {
+	return &Backfill{db.WithCollection(database.BackfillProgress)}
+}
+
+// SaveProgress insert the progress or replace the stored one with the same id,
+// empty omitempty fields are unset, so a new progress doesn't keep them from the finished one.
+func (r *Backfill) SaveProgress(ctx context.Context, p entity.BackfillProgress) error {
+	update := bson.D{{Key: "$set", Value: p}}
+
+	var unset bson.D
+	if p.FinishedAt == nil {
+		unset = append(unset, bson.E{Key: "finishedAt", Value: ""})
+	}
+	if len(p.FailedIds) == 0 {
+		unset = append(unset, bson.E{Key: "failedIds", Value: ""})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+
+	_, err := r.db.UpdateOne(
+		ctx,
+		bson.D{{Key: "id", Value: p.ID}},
+		update,
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
+
+// GetProgress get progress by id.
+func (r *Backfill) GetProgress(ctx context.Context, id string) (*entity.BackfillProgress, error) {
+	var p entity.BackfillProgress
+
+	if _, err := r.db.FindOne(ctx, bson.D{{Key: "id", Value: id}}, nil, &p); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProgressNotFound
+		}
+		return nil, err
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/database/mocks"
+	"go.sport-news/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestBackfill_SaveProgress(t *testing.T) {
+	finished := time.Date(2024, 2, 28, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		progress  entity.BackfillProgress
+		wantUnset bson.D
+	}{
+		{
+			name:      "new progress clears the finished one",
+			progress:  entity.BackfillProgress{ID: "t94", TeamID: "t94"},
+			wantUnset: bson.D{{Key: "finishedAt", Value: ""}, {Key: "failedIds", Value: ""}},
+		},
+		{
+			name:      "failed ids are kept",
+			progress:  entity.BackfillProgress{ID: "t94", FailedIds: []int{1}},
+			wantUnset: bson.D{{Key: "finishedAt", Value: ""}},
+		},
+		{
+			name:     "finished with failures",
+			progress: entity.BackfillProgress{ID: "t94", FinishedAt: &finished, FailedIds: []int{1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewDB(t)
+			progress := mocks.NewDB(t)
+			db.On("WithCollection", database.BackfillProgress).Return(progress)
+
+			update := bson.D{{Key: "$set", Value: tt.progress}}
+			if tt.wantUnset != nil {
+				update = append(update, bson.E{Key: "$unset", Value: tt.wantUnset})
+			}
+			progress.On(
+				"UpdateOne",
+				mock.Anything,
+				bson.D{{Key: "id", Value: "t94"}},
+				update,
+				options.Update().SetUpsert(true),
+			).Return(int64(1), nil)
+
+			assert.NoError(t, NewBackfillRepository(db).SaveProgress(context.Background(), tt.progress))
+		})
+	}
+}
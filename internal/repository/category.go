@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CategoryCount category with count of team articles in it.
+type CategoryCount struct {
+	Slug  string `bson:"_id" json:"slug"`
+	Name  string `bson:"name" json:"name"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// GetTeamCategories get categories of team articles sorted by count of articles desc.
+func (r *Repository) GetTeamCategories(ctx context.Context, team string) ([]CategoryCount, error) {
+	var c []CategoryCount
+
+	data, err := r.db.Aggregate(
+		ctx,
+		mongo.Pipeline{
+			{{Key: "$match", Value: bson.D{{Key: "teamId", Value: team}, notDeleted}}},
+			{{Key: "$unwind", Value: "$categories"}},
+			{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$categories.slug"},
+				{Key: "name", Value: bson.D{{Key: "$first", Value: "$categories.name"}}},
+				{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			}}},
+			{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		},
+		nil,
+		&c,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return *data.(*[]CategoryCount), nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+	"reflect"
+	"testing"
+)
+
+func TestRepository_GetTeamCategories(t *testing.T) {
+	categories := []CategoryCount{
+		{Slug: "club-news", Name: "Club News", Count: 10},
+		{Slug: "first-team", Name: "First Team", Count: 3},
+	}
+
+	tests := []struct {
+		name    string
+		want    []CategoryCount
+		wantErr bool
+	}{
+		{name: "team categories", want: categories},
+		{name: "aggregate error", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, d, ctx := setup(t)
+			var c []CategoryCount
+
+			call := d.On("Aggregate", ctx, mock.AnythingOfType("mongo.Pipeline"), nil, &c)
+			if tt.wantErr {
+				call.Return(nil, fmt.Errorf("some error"))
+			} else {
+				call.Run(func(args mock.Arguments) {
+					p := args.Get(1).(mongo.Pipeline)
+					if p[0][0].Key != "$match" || p[1][0].Key != "$unwind" {
+						t.Errorf("unexpected pipeline %v", p)
+					}
+				}).Return(&categories, nil)
+			}
+
+			got, err := r.GetTeamCategories(ctx, "t94")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTeamCategories() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTeamCategories() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+// NewsFilter optional filters of the team news list, zero value matches everything.
+type NewsFilter struct {
+	// Types category slugs.
+	Types       []string
+	OptaMatchID string
+	// From and To bounds of published, both inclusive.
+	From       *time.Time
+	To         *time.Time
+	HasVideo   *bool
+	HasGallery *bool
+}
+
+// bson return filter conditions.
+func (f NewsFilter) bson() bson.D {
+	var d bson.D
+
+	if len(f.Types) > 0 {
+		d = append(d, bson.E{Key: "categories.slug", Value: bson.D{{Key: "$in", Value: f.Types}}})
+	}
+	if f.OptaMatchID != "" {
+		d = append(d, bson.E{Key: "optaMatchId", Value: f.OptaMatchID})
+	}
+
+	var published bson.D
+	if f.From != nil {
+		published = append(published, bson.E{Key: "$gte", Value: *f.From})
+	}
+	if f.To != nil {
+		published = append(published, bson.E{Key: "$lte", Value: *f.To})
+	}
+	if published != nil {
+		d = append(d, bson.E{Key: "published", Value: published})
+	}
+
+	if f.HasVideo != nil {
+		d = append(d, hasMedia("videoUrl", *f.HasVideo))
+	}
+	if f.HasGallery != nil {
+		d = append(d, hasMedia("galleryUrls", *f.HasGallery))
+	}
+
+	return d
+}
+
+// hasMedia match articles with or without media in the field.
+func hasMedia(field string, has bool) bson.E {
+	return bson.E{Key: field + ".0", Value: bson.D{{Key: "$exists", Value: has}}}
+}
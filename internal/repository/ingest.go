@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/entity"
+)
+
+// ErrRunNotFound returned when there is no ingest run with the id.
+var ErrRunNotFound = errors.New("ingest run not found")
+
+//go:generate mockery --name IngestRepository
+type IngestRepository interface {
+	SaveRun(ctx context.Context, run entity.IngestRun) error
+	GetRuns(ctx context.Context, filter RunFilter, limit int64) ([]entity.IngestRun, error)
+	GetRun(ctx context.Context, id string) (*entity.IngestRun, error)
+	LastSuccessfulRun(ctx context.Context, team string) (*entity.IngestRun, error)
+}
+
+// RunFilter filters of the ingest runs list, empty fields don't filter.
+type RunFilter struct {
+	Team   string
+	Status string
+}
+
+type IngestRuns struct {
+	db database.DB
+}
+
+func NewIngestRepository(db database.DB) *IngestRuns {
+	return &IngestRuns{db.WithCollection(database.IngestRuns)}
+}
+
+// SaveRun insert the run or replace the stored one with the same id.
+func (r *IngestRuns) SaveRun(ctx context.Context, run entity.IngestRun) error {
+	_, err := r.db.UpdateOne(
+		ctx,
+		bson.D{{Key: "id", Value: run.ID}},
+		bson.D{{Key: "$set", Value: run}},
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
+
+// GetRuns get the latest runs first.
+func (r *IngestRuns) GetRuns(ctx context.Context, f RunFilter, limit int64) ([]entity.IngestRun, error) {
+	filter := bson.D{}
+	if f.Team != "" {
+		filter = append(filter, bson.E{Key: "teamId", Value: f.Team})
+	}
+	if f.Status != "" {
+		filter = append(filter, bson.E{Key: "status", Value: f.Status})
+	}
+
+	runs := make([]entity.IngestRun, 0)
+	if _, err := r.db.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}}).SetLimit(limit),
+		&runs,
+	); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// GetRun get run by id.
+func (r *IngestRuns) GetRun(ctx context.Context, id string) (*entity.IngestRun, error) {
+	return r.findOne(ctx, bson.D{{Key: "id", Value: id}}, nil)
+}
+
+// LastSuccessfulRun get the latest run of the team which synced the feed,
+// a not modified feed is synced too.
+func (r *IngestRuns) LastSuccessfulRun(ctx context.Context, team string) (*entity.IngestRun, error) {
+	return r.findOne(
+		ctx,
+		bson.D{
+			{Key: "teamId", Value: team},
+			{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{entity.IngestSuccess, entity.IngestNotModified}}}},
+		},
+		options.FindOne().SetSort(bson.D{{Key: "startedAt", Value: -1}}),
+	)
+}
+
+// findOne opts *options.FindOneOptions.
+func (r *IngestRuns) findOne(ctx context.Context, filter bson.D, opts interface{}) (*entity.IngestRun, error) {
+	var run entity.IngestRun
+
+	if _, err := r.db.FindOne(ctx, filter, opts, &run); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRunNotFound
+		}
+		return nil, err
+	}
+
+	return &run, nil
+}
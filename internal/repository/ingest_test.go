@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/database/mocks"
+	"go.sport-news/internal/entity"
+	"testing"
+)
+
+func setupIngest(t *testing.T) (*IngestRuns, *mocks.DB) {
+	db := mocks.NewDB(t)
+	runs := mocks.NewDB(t)
+	db.On("WithCollection", database.IngestRuns).Return(runs)
+
+	return NewIngestRepository(db), runs
+}
+
+func TestIngestRuns_SaveRun(t *testing.T) {
+	r, db := setupIngest(t)
+	run := entity.IngestRun{ID: "1", TeamID: "t94", Status: entity.IngestSuccess}
+
+	db.On(
+		"UpdateOne",
+		mock.Anything,
+		bson.D{{Key: "id", Value: "1"}},
+		bson.D{{Key: "$set", Value: run}},
+		options.Update().SetUpsert(true),
+	).Return(int64(1), nil)
+
+	assert.NoError(t, r.SaveRun(context.Background(), run))
+}
+
+func TestIngestRuns_GetRuns(t *testing.T) {
+	r, db := setupIngest(t)
+
+	db.On(
+		"Find",
+		mock.Anything,
+		bson.D{{Key: "teamId", Value: "t94"}, {Key: "status", Value: entity.IngestFailed}},
+		options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}}).SetLimit(10),
+		mock.Anything,
+	).Return(nil, nil)
+
+	runs, err := r.GetRuns(context.Background(), RunFilter{Team: "t94", Status: entity.IngestFailed}, 10)
+	assert.NoError(t, err)
+	assert.NotNil(t, runs)
+}
+
+func TestIngestRuns_GetRun(t *testing.T) {
+	r, db := setupIngest(t)
+
+	db.On("FindOne", mock.Anything, bson.D{{Key: "id", Value: "1"}}, nil, mock.Anything).
+		Return(nil, mongo.ErrNoDocuments)
+
+	_, err := r.GetRun(context.Background(), "1")
+	assert.ErrorIs(t, err, ErrRunNotFound)
+}
+
+func TestIngestRuns_LastSuccessfulRun(t *testing.T) {
+	r, db := setupIngest(t)
+
+	db.On(
+		"FindOne",
+		mock.Anything,
+		bson.D{
+			{Key: "teamId", Value: "t94"},
+			{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{entity.IngestSuccess, entity.IngestNotModified}}}},
+		},
+		options.FindOne().SetSort(bson.D{{Key: "startedAt", Value: -1}}),
+		mock.Anything,
+	).Return(&entity.IngestRun{ID: "1"}, nil)
+
+	run, err := r.LastSuccessfulRun(context.Background(), "t94")
+	assert.NoError(t, err)
+	assert.NotNil(t, run)
+}
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "go.sport-news/internal/entity"
+)
+
+// BackfillRepository is an autogenerated mock type for the BackfillRepository type
+type BackfillRepository struct {
+	mock.Mock
+}
+
+// GetProgress provides a mock function with given fields: ctx, id
+func (_m *BackfillRepository) GetProgress(ctx context.Context, id string) (*entity.BackfillProgress, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProgress")
+	}
+
+	var r0 *entity.BackfillProgress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.BackfillProgress, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.BackfillProgress); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.BackfillProgress)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveProgress provides a mock function with given fields: ctx, p
+func (_m *BackfillRepository) SaveProgress(ctx context.Context, p entity.BackfillProgress) error {
+	ret := _m.Called(ctx, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveProgress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.BackfillProgress) error); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBackfillRepository creates a new instance of BackfillRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBackfillRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BackfillRepository {
+	mock := &BackfillRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,139 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "go.sport-news/internal/entity"
+
+	repository "go.sport-news/internal/repository"
+)
+
+// IngestRepository is an autogenerated mock type for the IngestRepository type
+type IngestRepository struct {
+	mock.Mock
+}
+
+// GetRun provides a mock function with given fields: ctx, id
+func (_m *IngestRepository) GetRun(ctx context.Context, id string) (*entity.IngestRun, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRun")
+	}
+
+	var r0 *entity.IngestRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.IngestRun, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.IngestRun); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.IngestRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRuns provides a mock function with given fields: ctx, filter, limit
+func (_m *IngestRepository) GetRuns(ctx context.Context, filter repository.RunFilter, limit int64) ([]entity.IngestRun, error) {
+	ret := _m.Called(ctx, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRuns")
+	}
+
+	var r0 []entity.IngestRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.RunFilter, int64) ([]entity.IngestRun, error)); ok {
+		return rf(ctx, filter, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.RunFilter, int64) []entity.IngestRun); ok {
+		r0 = rf(ctx, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.IngestRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.RunFilter, int64) error); ok {
+		r1 = rf(ctx, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LastSuccessfulRun provides a mock function with given fields: ctx, team
+func (_m *IngestRepository) LastSuccessfulRun(ctx context.Context, team string) (*entity.IngestRun, error) {
+	ret := _m.Called(ctx, team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastSuccessfulRun")
+	}
+
+	var r0 *entity.IngestRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.IngestRun, error)); ok {
+		return rf(ctx, team)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.IngestRun); ok {
+		r0 = rf(ctx, team)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.IngestRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, team)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveRun provides a mock function with given fields: ctx, run
+func (_m *IngestRepository) SaveRun(ctx context.Context, run entity.IngestRun) error {
+	ret := _m.Called(ctx, run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.IngestRun) error); ok {
+		r0 = rf(ctx, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIngestRepository creates a new instance of IngestRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIngestRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IngestRepository {
+	mock := &IngestRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,10 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	entity "go.sport-news/internal/entity"
+
+	repository "go.sport-news/internal/repository"
+
+	time "time"
 )
 
 // NewsRepository is an autogenerated mock type for the NewsRepository type
@@ -14,6 +18,34 @@ type NewsRepository struct {
 	mock.Mock
 }
 
+// DeleteAll provides a mock function with given fields: ctx
+func (_m *NewsRepository) DeleteAll(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAll")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Exist provides a mock function with given fields: ctx, field, value
 func (_m *NewsRepository) Exist(ctx context.Context, field string, value interface{}) (bool, error) {
 	ret := _m.Called(ctx, field, value)
@@ -42,29 +74,29 @@ func (_m *NewsRepository) Exist(ctx context.Context, field string, value interfa
 	return r0, r1
 }
 
-// GetAllExternalIds provides a mock function with given fields: ctx
-func (_m *NewsRepository) GetAllExternalIds(ctx context.Context) (map[int]int, error) {
-	ret := _m.Called(ctx)
+// GetAllExternalIds provides a mock function with given fields: ctx, team
+func (_m *NewsRepository) GetAllExternalIds(ctx context.Context, team string) (map[int]repository.ExternalState, error) {
+	ret := _m.Called(ctx, team)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllExternalIds")
 	}
 
-	var r0 map[int]int
+	var r0 map[int]repository.ExternalState
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (map[int]int, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (map[int]repository.ExternalState, error)); ok {
+		return rf(ctx, team)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) map[int]int); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[int]repository.ExternalState); ok {
+		r0 = rf(ctx, team)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[int]int)
+			r0 = ret.Get(0).(map[int]repository.ExternalState)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, team)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -72,24 +104,54 @@ func (_m *NewsRepository) GetAllExternalIds(ctx context.Context) (map[int]int, e
 	return r0, r1
 }
 
-// GetTeamNews provides a mock function with given fields: ctx, team
-func (_m *NewsRepository) GetTeamNews(ctx context.Context, team string) ([]entity.Article, error) {
-	ret := _m.Called(ctx, team)
+// GetArticlesAfter provides a mock function with given fields: ctx, team, after, ids, limit
+func (_m *NewsRepository) GetArticlesAfter(ctx context.Context, team string, after int, ids []int, limit int64) ([]entity.Article, error) {
+	ret := _m.Called(ctx, team, after, ids, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTeamNews")
+		panic("no return value specified for GetArticlesAfter")
 	}
 
 	var r0 []entity.Article
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.Article, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, []int, int64) ([]entity.Article, error)); ok {
+		return rf(ctx, team, after, ids, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, []int, int64) []entity.Article); ok {
+		r0 = rf(ctx, team, after, ids, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, []int, int64) error); ok {
+		r1 = rf(ctx, team, after, ids, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeamCategories provides a mock function with given fields: ctx, team
+func (_m *NewsRepository) GetTeamCategories(ctx context.Context, team string) ([]repository.CategoryCount, error) {
+	ret := _m.Called(ctx, team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamCategories")
+	}
+
+	var r0 []repository.CategoryCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]repository.CategoryCount, error)); ok {
 		return rf(ctx, team)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.Article); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) []repository.CategoryCount); ok {
 		r0 = rf(ctx, team)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entity.Article)
+			r0 = ret.Get(0).([]repository.CategoryCount)
 		}
 	}
 
@@ -102,6 +164,36 @@ func (_m *NewsRepository) GetTeamNews(ctx context.Context, team string) ([]entit
 	return r0, r1
 }
 
+// GetTeamNews provides a mock function with given fields: ctx, team, filter, page
+func (_m *NewsRepository) GetTeamNews(ctx context.Context, team string, filter repository.NewsFilter, page repository.Page) (*repository.PageResult, error) {
+	ret := _m.Called(ctx, team, filter, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamNews")
+	}
+
+	var r0 *repository.PageResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, repository.NewsFilter, repository.Page) (*repository.PageResult, error)); ok {
+		return rf(ctx, team, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, repository.NewsFilter, repository.Page) *repository.PageResult); ok {
+		r0 = rf(ctx, team, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.PageResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, repository.NewsFilter, repository.Page) error); ok {
+		r1 = rf(ctx, team, filter, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTeamNewsByID provides a mock function with given fields: ctx, team, id
 func (_m *NewsRepository) GetTeamNewsByID(ctx context.Context, team string, id string) (*entity.Article, error) {
 	ret := _m.Called(ctx, team, id)
@@ -150,6 +242,138 @@ func (_m *NewsRepository) InsertArticles(ctx context.Context, articles []entity.
 	return r0
 }
 
+// RetractArticles provides a mock function with given fields: ctx, team, ids, reason
+func (_m *NewsRepository) RetractArticles(ctx context.Context, team string, ids []int, reason string) (int64, error) {
+	ret := _m.Called(ctx, team, ids, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetractArticles")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int, string) (int64, error)); ok {
+		return rf(ctx, team, ids, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int, string) int64); ok {
+		r0 = rf(ctx, team, ids, reason)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []int, string) error); ok {
+		r1 = rf(ctx, team, ids, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetractMissing provides a mock function with given fields: ctx, team, ids, since, seenBefore
+func (_m *NewsRepository) RetractMissing(ctx context.Context, team string, ids []int, since time.Time, seenBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, team, ids, since, seenBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetractMissing")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int, time.Time, time.Time) (int64, error)); ok {
+		return rf(ctx, team, ids, since, seenBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int, time.Time, time.Time) int64); ok {
+		r0 = rf(ctx, team, ids, since, seenBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []int, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, team, ids, since, seenBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchTeamNews provides a mock function with given fields: ctx, team, query, page, limit
+func (_m *NewsRepository) SearchTeamNews(ctx context.Context, team string, query string, page int64, limit int64) (*repository.SearchResult, error) {
+	ret := _m.Called(ctx, team, query, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTeamNews")
+	}
+
+	var r0 *repository.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, int64) (*repository.SearchResult, error)); ok {
+		return rf(ctx, team, query, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, int64) *repository.SearchResult); ok {
+		r0 = rf(ctx, team, query, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64, int64) error); ok {
+		r1 = rf(ctx, team, query, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TouchArticles provides a mock function with given fields: ctx, team, ids, seen
+func (_m *NewsRepository) TouchArticles(ctx context.Context, team string, ids []int, seen time.Time) error {
+	ret := _m.Called(ctx, team, ids, seen)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchArticles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int, time.Time) error); ok {
+		r0 = rf(ctx, team, ids, seen)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertArticles provides a mock function with given fields: ctx, articles
+func (_m *NewsRepository) UpsertArticles(ctx context.Context, articles []entity.Article) (int64, error) {
+	ret := _m.Called(ctx, articles)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertArticles")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.Article) (int64, error)); ok {
+		return rf(ctx, articles)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.Article) int64); ok {
+		r0 = rf(ctx, articles)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []entity.Article) error); ok {
+		r1 = rf(ctx, articles)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewNewsRepository creates a new instance of NewsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewNewsRepository(t interface {
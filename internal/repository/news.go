@@ -2,22 +2,45 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.sport-news/internal/database"
 	"go.sport-news/internal/entity"
+	"time"
 )
 
+// ErrArticleNotFound returned when the team has no article with the id.
+var ErrArticleNotFound = errors.New("article not found")
+
 //go:generate mockery --name NewsRepository
 type NewsRepository interface {
 	Exist(ctx context.Context, field string, value any) (bool, error)
-	GetTeamNews(ctx context.Context, team string) (*[]entity.Article, error)
+	GetTeamNews(ctx context.Context, team string, filter NewsFilter, page Page) (*PageResult, error)
 	GetTeamNewsByID(ctx context.Context, team, id string) (*entity.Article, error)
-	GetAllExternalIds(ctx context.Context) (map[int]int, error)
+	SearchTeamNews(ctx context.Context, team, query string, page, limit int64) (*SearchResult, error)
+	GetTeamCategories(ctx context.Context, team string) ([]CategoryCount, error)
+	GetAllExternalIds(ctx context.Context, team string) (map[int]ExternalState, error)
+	GetArticlesAfter(ctx context.Context, team string, after int, ids []int, limit int64) ([]entity.Article, error)
 	InsertArticles(ctx context.Context, articles []entity.Article) error
+	UpsertArticles(ctx context.Context, articles []entity.Article) (int64, error)
+	TouchArticles(ctx context.Context, team string, ids []int, seen time.Time) error
+	RetractArticles(ctx context.Context, team string, ids []int, reason string) (int64, error)
+	RetractMissing(ctx context.Context, team string, ids []int, since, seenBefore time.Time) (int64, error)
 	DeleteAll(ctx context.Context) (int64, error)
 }
 
+// ExternalState stored state of an external article, used to sync with the feed.
+type ExternalState struct {
+	ExternalId int        `bson:"externalId"`
+	Updated    time.Time  `bson:"updated"`
+	DeletedAt  *time.Time `bson:"deletedAt"`
+}
+
+// notDeleted filter for articles which were not retracted.
+var notDeleted = bson.E{Key: "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}}
+
 type Repository struct {
 	db database.DB
 }
@@ -26,20 +49,48 @@ func NewNewsRepository(db database.DB) *Repository {
 	return &Repository{db}
 }
 
-// GetTeamNews get articles by team.
-func (r *Repository) GetTeamNews(ctx context.Context, team string) (*[]entity.Article, error) {
-	var a []entity.Article
-	count := int64(50)
+// GetTeamNews get page of filtered articles by team sorted by published desc.
+func (r *Repository) GetTeamNews(ctx context.Context, team string, f NewsFilter, page Page) (*PageResult, error) {
+	filter := append(bson.D{
+		{Key: "teamId", Value: team},
+		notDeleted,
+	}, f.bson()...)
+
+	total, err := r.db.CountDocuments(ctx, filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		a      []entity.Article
+		limit  = page.Size()
+		count  = limit + 1 // one more row to know if the next page exists
+		order  = -1
+		before = page.Cursor != nil && page.Cursor.Before
+	)
+	if page.Cursor != nil {
+		op := "$lt"
+		if before {
+			op = "$gt"
+			order = 1
+		}
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "published", Value: bson.D{{Key: op, Value: page.Cursor.Published}}}},
+			bson.D{
+				{Key: "published", Value: page.Cursor.Published},
+				{Key: "id", Value: bson.D{{Key: op, Value: page.Cursor.ID}}},
+			},
+		}})
+	}
 
 	data, err := r.db.Find(
 		ctx,
-		bson.D{
-			{Key: "teamId", Value: team},
-		},
+		filter,
 		&options.FindOptions{
 			Limit: &count,
 			Sort: bson.D{
-				{Key: "published", Value: -1},
+				{Key: "published", Value: order},
+				{Key: "id", Value: order},
 			},
 		},
 		&a,
@@ -48,7 +99,41 @@ func (r *Repository) GetTeamNews(ctx context.Context, team string) (*[]entity.Ar
 		return nil, err
 	}
 
-	return data.(*[]entity.Article), err
+	articles := *data.(*[]entity.Article)
+	more := int64(len(articles)) > limit
+	if more {
+		articles = articles[:limit]
+	}
+	if before {
+		for i, j := 0, len(articles)-1; i < j; i, j = i+1, j-1 {
+			articles[i], articles[j] = articles[j], articles[i]
+		}
+	}
+
+	res := &PageResult{Articles: articles, TotalItems: total}
+	if len(articles) == 0 {
+		return res, nil
+	}
+
+	first, last := articles[0], articles[len(articles)-1]
+	switch {
+	case page.Cursor == nil:
+		if more {
+			res.Next = cursorAt(last, false)
+		}
+	case before:
+		res.Next = cursorAt(last, false)
+		if more {
+			res.Prev = cursorAt(first, true)
+		}
+	default:
+		res.Prev = cursorAt(first, true)
+		if more {
+			res.Next = cursorAt(last, false)
+		}
+	}
+
+	return res, nil
 }
 
 // GetTeamNewsByID get article by team and id.
@@ -60,10 +145,14 @@ func (r *Repository) GetTeamNewsByID(ctx context.Context, team, id string) (*ent
 		bson.D{
 			{Key: "teamId", Value: team},
 			{Key: "id", Value: id},
+			notDeleted,
 		},
 		nil,
 		&a,
 	)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrArticleNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -71,43 +160,214 @@ func (r *Repository) GetTeamNewsByID(ctx context.Context, team, id string) (*ent
 	return article.(*entity.Article), err
 }
 
-// GetAllExternalIds get all ids for match data.
-func (r *Repository) GetAllExternalIds(ctx context.Context) (map[int]int, error) {
-	type resp = []struct {
-		ExternalId int `bson:"externalId"`
-	}
-	var d resp
+// GetAllExternalIds get all team ids with their last update time for match data.
+// External ids are unique only inside a team.
+func (r *Repository) GetAllExternalIds(ctx context.Context, team string) (map[int]ExternalState, error) {
+	var d []ExternalState
 
-	data, err := r.db.Find(ctx, bson.M{}, &options.FindOptions{Projection: bson.D{{Key: "externalId", Value: 1}}}, &d)
+	data, err := r.db.Find(
+		ctx,
+		bson.D{{Key: "teamId", Value: team}},
+		&options.FindOptions{Projection: bson.D{
+			{Key: "externalId", Value: 1},
+			{Key: "updated", Value: 1},
+			{Key: "deletedAt", Value: 1},
+		}},
+		&d,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	var oldIds map[int]int
-	switch v := data.(type) {
-	case resp:
-		oldIds = make(map[int]int, len(v))
-		for _, item := range data.(resp) {
-			oldIds[item.ExternalId] = item.ExternalId
-		}
-	default:
-		return make(map[int]int), nil
+	states := *data.(*[]ExternalState)
+	oldIds := make(map[int]ExternalState, len(states))
+	for _, item := range states {
+		oldIds[item.ExternalId] = item
 	}
 
 	return oldIds, nil
 }
 
-// InsertArticles insert many articles to db.
+// GetArticlesAfter get stored articles of the team with external id greater than after,
+// sorted by external id. Empty ids don't filter.
+func (r *Repository) GetArticlesAfter(
+	ctx context.Context,
+	team string,
+	after int,
+	ids []int,
+	limit int64,
+) ([]entity.Article, error) {
+	external := bson.D{{Key: "$gt", Value: after}}
+	if len(ids) > 0 {
+		external = append(external, bson.E{Key: "$in", Value: ids})
+	}
+
+	articles := make([]entity.Article, 0)
+	if _, err := r.db.Find(
+		ctx,
+		bson.D{
+			{Key: "teamId", Value: team},
+			{Key: "externalId", Value: external},
+			notDeleted,
+		},
+		options.Find().SetSort(bson.D{{Key: "externalId", Value: 1}}).SetLimit(limit),
+		&articles,
+	); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// InsertArticles insert articles which are not stored yet, stored ones are left as is,
+// so inserting the same articles twice is a no-op.
 func (r *Repository) InsertArticles(ctx context.Context, articles []entity.Article) error {
-	var interfaceSlice []interface{} // todo: мб можно убрать костыль
+	filters := make([]interface{}, 0, len(articles))
+	updates := make([]interface{}, 0, len(articles))
 	for _, a := range articles {
-		interfaceSlice = append(interfaceSlice, a)
+		filters = append(filters, articleFilter(a))
+		updates = append(updates, bson.D{{Key: "$setOnInsert", Value: a}})
+	}
+
+	_, err := r.db.BulkUpsert(ctx, filters, updates)
+
+	return err
+}
+
+// UpsertArticles insert new articles and update stored ones matched by team and external id.
+// Stored articles keep their id. Returns count of modified and inserted articles.
+func (r *Repository) UpsertArticles(ctx context.Context, articles []entity.Article) (int64, error) {
+	filters := make([]interface{}, 0, len(articles))
+	updates := make([]interface{}, 0, len(articles))
+	for _, a := range articles {
+		update, err := articleUpdate(a)
+		if err != nil {
+			return 0, err
+		}
+		filters = append(filters, articleFilter(a))
+		updates = append(updates, update)
 	}
 
-	return r.db.InsertMany(ctx, interfaceSlice)
+	return r.db.BulkUpsert(ctx, filters, updates)
 }
 
-// Exist check for exist field with value on db.
+// TouchArticles mark articles as seen in the feed.
+func (r *Repository) TouchArticles(ctx context.Context, team string, ids []int, seen time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.UpdateMany(
+		ctx,
+		bson.D{
+			{Key: "teamId", Value: team},
+			{Key: "externalId", Value: bson.D{{Key: "$in", Value: ids}}},
+		},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "lastSeen", Value: seen}}}},
+		nil,
+	)
+
+	return err
+}
+
+// RetractArticles soft delete articles by external ids with the reason.
+func (r *Repository) RetractArticles(ctx context.Context, team string, ids []int, reason string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	return r.retract(
+		ctx,
+		bson.D{
+			{Key: "teamId", Value: team},
+			{Key: "externalId", Value: bson.D{{Key: "$in", Value: ids}}},
+			notDeleted,
+		},
+		reason,
+	)
+}
+
+// RetractMissing soft delete articles which are not in ids,
+// were published after since (so they must be in the feed)
+// and were last seen in the feed before seenBefore.
+func (r *Repository) RetractMissing(ctx context.Context, team string, ids []int, since, seenBefore time.Time) (int64, error) {
+	return r.retract(
+		ctx,
+		bson.D{
+			{Key: "teamId", Value: team},
+			{Key: "externalId", Value: bson.D{{Key: "$nin", Value: ids}}},
+			{Key: "published", Value: bson.D{{Key: "$gte", Value: since}}},
+			{Key: "lastSeen", Value: bson.D{{Key: "$lt", Value: seenBefore}}},
+			notDeleted,
+		},
+		entity.DeleteReasonMissing,
+	)
+}
+
+func (r *Repository) retract(ctx context.Context, filter bson.D, reason string) (int64, error) {
+	return r.db.UpdateMany(
+		ctx,
+		filter,
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "deletedAt", Value: time.Now().UTC()},
+			{Key: "deleteReason", Value: reason},
+		}}},
+		nil,
+	)
+}
+
+// articleFilter match stored article, external ids are unique inside a team.
+func articleFilter(a entity.Article) bson.D {
+	return bson.D{
+		{Key: "teamId", Value: a.TeamID},
+		{Key: "externalId", Value: a.ExternalId},
+	}
+}
+
+// clearable omitempty fields of the article, they are unset when the re-fetched article has none,
+// so removed media, match links and blocks don't stay stored.
+var clearable = []string{"optaMatchId", "blocks", "galleryUrls", "videoUrl"}
+
+// articleUpdate make update document from article,
+// id is set only on insert so stored articles keep it,
+// retraction is cleared because the article is published again.
+func articleUpdate(a entity.Article) (bson.D, error) {
+	raw, err := bson.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.D
+	if err = bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	set := make(bson.D, 0, len(doc))
+	present := make(map[string]bool, len(doc))
+	for _, e := range doc {
+		present[e.Key] = true
+		switch e.Key {
+		case "id", "deletedAt", "deleteReason":
+		default:
+			set = append(set, e)
+		}
+	}
+
+	unset := bson.D{{Key: "deletedAt", Value: ""}, {Key: "deleteReason", Value: ""}}
+	for _, k := range clearable {
+		if !present[k] {
+			unset = append(unset, bson.E{Key: k, Value: ""})
+		}
+	}
+
+	return bson.D{
+		{Key: "$set", Value: set},
+		{Key: "$setOnInsert", Value: bson.D{{Key: "id", Value: a.ID}}},
+		{Key: "$unset", Value: unset},
+	}, nil
+}
+
+// Exist check for exist field with value on db, a missing row isn't an error.
 func (r *Repository) Exist(ctx context.Context, field string, value any) (bool, error) {
 	var a entity.Article
 	_, err := r.db.FindOne(
@@ -118,6 +378,9 @@ func (r *Repository) Exist(ctx context.Context, field string, value any) (bool,
 		nil,
 		&a,
 	)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
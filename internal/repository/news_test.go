@@ -6,7 +6,9 @@ import (
 	"github.com/chapsuk/grace"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.sport-news/internal/database/mocks"
 	"go.sport-news/internal/entity"
@@ -38,6 +40,7 @@ func TestRepository_Exist(t *testing.T) {
 
 	tests := []struct {
 		name    string
+		findErr error
 		want    bool
 		wantErr bool
 	}{
@@ -48,6 +51,13 @@ func TestRepository_Exist(t *testing.T) {
 		},
 		{
 			name:    "Test don't exist",
+			findErr: mongo.ErrNoDocuments,
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name:    "Test find error",
+			findErr: fmt.Errorf("timeout"),
 			want:    false,
 			wantErr: true,
 		},
@@ -67,8 +77,8 @@ func TestRepository_Exist(t *testing.T) {
 				&a,
 			)
 
-			if tt.wantErr {
-				c.Return(nil, fmt.Errorf("not found"))
+			if tt.findErr != nil {
+				c.Return(nil, tt.findErr)
 			} else {
 				c.Return(entity.Article{
 					ID:          "",
@@ -101,20 +111,24 @@ func TestRepository_Exist(t *testing.T) {
 }
 
 func TestRepository_GetAllExternalIds(t *testing.T) {
+	updated := time.Date(2024, 2, 28, 10, 3, 13, 0, time.UTC)
 
 	tests := []struct {
 		name string
-		want map[int]int
+		want map[int]ExternalState
 		flag bool
 	}{
 		{
 			name: "Test return extends ids",
-			want: map[int]int{1: 1, 2: 2, 3: 3, 4: 4},
+			want: map[int]ExternalState{
+				1: {ExternalId: 1, Updated: updated},
+				2: {ExternalId: 2},
+			},
 			flag: true,
 		},
 		{
 			name: "Test return empty ids",
-			want: map[int]int{},
+			want: map[int]ExternalState{},
 			flag: false,
 		},
 	}
@@ -122,24 +136,27 @@ func TestRepository_GetAllExternalIds(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			r, d, ctx := setup(t)
 
-			type re = []struct {
-				ExternalId int `bson:"externalId"`
-			}
+			type re = []ExternalState
 			var resp re
 			data := d.On(
 				"Find",
 				ctx,
-				bson.M{},
-				&options.FindOptions{Projection: bson.D{{Key: "externalId", Value: 1}}},
+				bson.D{{Key: "teamId", Value: "t94"}},
+				&options.FindOptions{Projection: bson.D{
+					{Key: "externalId", Value: 1},
+					{Key: "updated", Value: 1},
+					{Key: "deletedAt", Value: 1},
+				}},
 				&resp,
 			)
+			// mongo returns the pointer it was given
 			if tt.flag {
-				data.Return(re{{ExternalId: 1}, {ExternalId: 2}, {ExternalId: 3}, {ExternalId: 4}}, nil)
+				data.Return(&re{{ExternalId: 1, Updated: updated}, {ExternalId: 2}}, nil)
 			} else {
-				data.Return(re{}, nil)
+				data.Return(&re{}, nil)
 			}
 
-			got, _ := r.GetAllExternalIds(ctx)
+			got, _ := r.GetAllExternalIds(ctx, "t94")
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetAllExternalIds() got = %v, want %v", got, tt.want)
 			}
@@ -148,90 +165,104 @@ func TestRepository_GetAllExternalIds(t *testing.T) {
 }
 
 func TestRepository_GetTeamNews(t *testing.T) {
+	published := time.Date(2024, 2, 28, 9, 58, 47, 0, time.UTC)
 	articles := []entity.Article{
-		{
-			ID:          "1",
-			TeamID:      "t93",
-			ExternalId:  0,
-			OptaMatchID: nil,
-			Title:       "",
-			Type:        nil,
-			Teaser:      "",
-			Content:     "",
-			URL:         "",
-			ImageURL:    "",
-			GalleryUrls: nil,
-			VideoURL:    nil,
-			Published:   time.Time{},
-		},
-		{
-			ID:          "2",
-			TeamID:      "t94",
-			ExternalId:  0,
-			OptaMatchID: nil,
-			Title:       "",
-			Type:        nil,
-			Teaser:      "",
-			Content:     "",
-			URL:         "",
-			ImageURL:    "",
-			GalleryUrls: nil,
-			VideoURL:    nil,
-			Published:   time.Time{},
-		},
+		{ID: "3", TeamID: "t94", Published: published},
+		{ID: "2", TeamID: "t94", Published: published.Add(-time.Hour)},
+		{ID: "1", TeamID: "t94", Published: published.Add(-2 * time.Hour)},
 	}
-	count := int64(50)
-	var a []entity.Article
+	team := bson.E{Key: "teamId", Value: "t94"}
+	base := bson.D{team, notDeleted}
+	keyset := func(op string, c *Cursor) bson.E {
+		return bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "published", Value: bson.D{{Key: op, Value: c.Published}}}},
+			bson.D{
+				{Key: "published", Value: c.Published},
+				{Key: "id", Value: bson.D{{Key: op, Value: c.ID}}},
+			},
+		}}
+	}
+	after := cursorAt(articles[0], false)
+	before := cursorAt(articles[2], true)
 
 	tests := []struct {
-		name  string
-		team  string
-		index int
-		want  *[]entity.Article
+		name   string
+		page   Page
+		filter bson.D
+		order  int
+		found  []entity.Article
+		want   *PageResult
 	}{
 		{
-			name:  "Get teams",
-			team:  "t94",
-			index: 0,
-			want:  &[]entity.Article{articles[0]},
+			name:   "first page with next",
+			page:   Page{Limit: 2},
+			filter: base,
+			order:  -1,
+			found:  articles,
+			want: &PageResult{
+				Articles:   articles[:2],
+				TotalItems: 3,
+				Next:       cursorAt(articles[1], false),
+			},
 		},
 		{
-			name:  "Get teams",
-			team:  "t93",
-			index: 1,
-			want:  &[]entity.Article{articles[1]},
+			name:   "last page after cursor",
+			page:   Page{Limit: 2, Cursor: after},
+			filter: bson.D{team, notDeleted, keyset("$lt", after)},
+			order:  -1,
+			found:  articles[1:],
+			want: &PageResult{
+				Articles:   articles[1:],
+				TotalItems: 3,
+				Prev:       cursorAt(articles[1], true),
+			},
 		},
 		{
-			name:  "Get teams",
-			team:  "t934",
-			index: -1,
-			want:  &[]entity.Article{},
+			name:   "first page before cursor",
+			page:   Page{Limit: 2, Cursor: before},
+			filter: bson.D{team, notDeleted, keyset("$gt", before)},
+			order:  1,
+			found:  []entity.Article{articles[1], articles[0]},
+			want: &PageResult{
+				Articles:   articles[:2],
+				TotalItems: 3,
+				Next:       cursorAt(articles[1], false),
+			},
+		},
+		{
+			name:   "empty page",
+			page:   Page{},
+			filter: base,
+			order:  -1,
+			found:  []entity.Article{},
+			want: &PageResult{
+				Articles:   []entity.Article{},
+				TotalItems: 3,
+			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r, d, ctx := setup(t)
+			var a []entity.Article
+			count := tt.page.Size() + 1
 
-			c := d.On(
+			d.On("CountDocuments", ctx, base, nil).Return(int64(3), nil)
+			d.On(
 				"Find",
 				ctx,
-				bson.D{
-					{Key: "teamId", Value: tt.team},
-				},
+				tt.filter,
 				&options.FindOptions{
 					Limit: &count,
 					Sort: bson.D{
-						{Key: "published", Value: -1},
+						{Key: "published", Value: tt.order},
+						{Key: "id", Value: tt.order},
 					},
 				},
 				&a,
-			)
-			if tt.index != -1 {
-				c.Return(&[]entity.Article{articles[tt.index]}, nil)
-			} else {
-				c.Return(&[]entity.Article{}, nil)
-			}
-			got, err := r.GetTeamNews(ctx, tt.team)
+			).Return(&tt.found, nil)
+
+			got, err := r.GetTeamNews(ctx, "t94", NewsFilter{}, tt.page)
 			if err != nil {
 				t.Errorf("GetTeamNews() error = %v", err)
 				return
@@ -243,6 +274,55 @@ func TestRepository_GetTeamNews(t *testing.T) {
 	}
 }
 
+func TestNewsFilter_bson(t *testing.T) {
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	yes, no := true, false
+
+	tests := []struct {
+		name   string
+		filter NewsFilter
+		want   bson.D
+	}{
+		{name: "empty filter", filter: NewsFilter{}, want: nil},
+		{
+			name: "all filters",
+			filter: NewsFilter{
+				Types:       []string{"club-news"},
+				OptaMatchID: "g2370928",
+				From:        &from,
+				To:          &to,
+				HasVideo:    &yes,
+				HasGallery:  &no,
+			},
+			want: bson.D{
+				{Key: "categories.slug", Value: bson.D{{Key: "$in", Value: []string{"club-news"}}}},
+				{Key: "optaMatchId", Value: "g2370928"},
+				{Key: "published", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lte", Value: to}}},
+				{Key: "videoUrl.0", Value: bson.D{{Key: "$exists", Value: true}}},
+				{Key: "galleryUrls.0", Value: bson.D{{Key: "$exists", Value: false}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.bson())
+		})
+	}
+}
+
+func TestCursor_Encode(t *testing.T) {
+	c := Cursor{Published: time.Date(2024, 2, 28, 9, 58, 47, 0, time.UTC), ID: uuid.New().String(), Before: true}
+
+	got, err := DecodeCursor(c.Encode())
+	if assert.NoError(t, err) {
+		assert.Equal(t, &c, got)
+	}
+
+	_, err = DecodeCursor("not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
 func TestRepository_GetTeamNewsByID(t *testing.T) {
 	type args struct {
 		team string
@@ -309,6 +389,7 @@ func TestRepository_GetTeamNewsByID(t *testing.T) {
 				bson.D{
 					{Key: "teamId", Value: tt.args.team},
 					{Key: "id", Value: tt.args.id},
+					notDeleted,
 				},
 				nil,
 				&a,
@@ -317,7 +398,7 @@ func TestRepository_GetTeamNewsByID(t *testing.T) {
 				c.Return(nil, fmt.Errorf("not found"))
 			}
 			if tt.args.id == "" {
-				c.Return(nil, fmt.Errorf("not found"))
+				c.Return(nil, mongo.ErrNoDocuments)
 			}
 			if tt.args.team != "" && tt.args.id != "" {
 				c.Return(&e, nil)
@@ -371,14 +452,16 @@ func TestRepository_InsertArticles(t *testing.T) {
 					Published:   time.Time{},
 				},
 			}
-			var i []interface{}
-			i = append(i, a[0])
-
-			c := d.On("InsertMany", ctx, i)
+			c := d.On(
+				"BulkUpsert",
+				ctx,
+				[]interface{}{bson.D{{Key: "teamId", Value: ""}, {Key: "externalId", Value: 0}}},
+				[]interface{}{bson.D{{Key: "$setOnInsert", Value: a[0]}}},
+			)
 			if tt.wantErr {
-				c.Return(fmt.Errorf("some error"))
+				c.Return(int64(0), fmt.Errorf("some error"))
 			} else {
-				c.Return(nil)
+				c.Return(int64(1), nil)
 			}
 
 			if err := r.InsertArticles(ctx, a); (err != nil) != tt.wantErr {
@@ -388,6 +471,144 @@ func TestRepository_InsertArticles(t *testing.T) {
 	}
 }
 
+func TestRepository_UpsertArticles(t *testing.T) {
+	a := entity.Article{
+		ID:         "id",
+		TeamID:     "t94",
+		ExternalId: 1,
+		Title:      "title",
+		Published:  time.Date(2024, 2, 28, 9, 58, 47, 0, time.UTC),
+		Updated:    time.Date(2024, 2, 28, 10, 3, 13, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "check success upsert", want: 1, wantErr: false},
+		{name: "check error upsert", want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, d, ctx := setup(t)
+
+			update, err := articleUpdate(a)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for _, e := range update[0].Value.(bson.D) {
+				assert.NotEqual(t, "id", e.Key, "id must be set only on insert")
+			}
+			assert.Equal(t, bson.D{{Key: "id", Value: a.ID}}, update[1].Value)
+
+			c := d.On(
+				"BulkUpsert",
+				ctx,
+				[]interface{}{bson.D{
+					{Key: "teamId", Value: a.TeamID},
+					{Key: "externalId", Value: a.ExternalId},
+				}},
+				[]interface{}{update},
+			)
+			if tt.wantErr {
+				c.Return(int64(0), fmt.Errorf("some error"))
+			} else {
+				c.Return(int64(1), nil)
+			}
+
+			got, err := r.UpsertArticles(ctx, []entity.Article{a})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpsertArticles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UpsertArticles() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_UpsertArticles_removedMedia(t *testing.T) {
+	r, d, ctx := setup(t)
+
+	// the stored article had a video, a gallery and a match, the club removed them
+	opta := "g1"
+	video := entity.Article{
+		TeamID:      "t94",
+		ExternalId:  1,
+		OptaMatchID: &opta,
+		VideoURL:    entity.MediaList{entity.NewMedia("https://youtu.be/1", entity.MediaEmbed)},
+		GalleryUrls: entity.MediaList{entity.NewMedia("https://club.test/1.jpg", entity.MediaImage)},
+	}
+	removed := entity.Article{TeamID: "t94", ExternalId: 1}
+
+	update, err := articleUpdate(video)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, bson.D{{Key: "deletedAt", Value: ""}, {Key: "deleteReason", Value: ""}, {Key: "blocks", Value: ""}}, update[2].Value)
+
+	update, err = articleUpdate(removed)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, bson.D{
+		{Key: "deletedAt", Value: ""},
+		{Key: "deleteReason", Value: ""},
+		{Key: "optaMatchId", Value: ""},
+		{Key: "blocks", Value: ""},
+		{Key: "galleryUrls", Value: ""},
+		{Key: "videoUrl", Value: ""},
+	}, update[2].Value)
+
+	d.On("BulkUpsert", ctx, []interface{}{articleFilter(removed)}, []interface{}{update}).Return(int64(1), nil)
+
+	got, err := r.UpsertArticles(ctx, []entity.Article{removed})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), got)
+}
+
+func TestRepository_RetractArticles(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []int
+		want int64
+	}{
+		{name: "retract articles", ids: []int{1, 2}, want: 2},
+		{name: "nothing to retract", ids: nil, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, d, ctx := setup(t)
+
+			if len(tt.ids) > 0 {
+				d.On(
+					"UpdateMany",
+					ctx,
+					bson.D{
+						{Key: "teamId", Value: "t94"},
+						{Key: "externalId", Value: bson.D{{Key: "$in", Value: tt.ids}}},
+						notDeleted,
+					},
+					mock.MatchedBy(func(update bson.D) bool {
+						set := update[0].Value.(bson.D)
+						return update[0].Key == "$set" && set[1].Value == entity.DeleteReasonUnpublished
+					}),
+					nil,
+				).Return(int64(len(tt.ids)), nil)
+			}
+
+			got, err := r.RetractArticles(ctx, "t94", tt.ids, entity.DeleteReasonUnpublished)
+			if err != nil {
+				t.Errorf("RetractArticles() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RetractArticles() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestRepository_DeleteAll(t *testing.T) {
 	tests := []struct {
 		name    string
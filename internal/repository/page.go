@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go.sport-news/internal/entity"
+	"time"
+)
+
+const (
+	// DefaultPageLimit used when the page limit is not set.
+	DefaultPageLimit int64 = 50
+	// MaxPageLimit the biggest page a client can ask for.
+	MaxPageLimit int64 = 100
+)
+
+// ErrInvalidCursor returned when a cursor can't be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points to an article in the list sorted by published desc.
+// Keyset cursors stay stable while new articles are inserted.
+type Cursor struct {
+	Published time.Time
+	ID        string
+	// Before page goes to the newer articles, otherwise to the older ones.
+	Before bool
+}
+
+// Page request for a slice of the articles list.
+type Page struct {
+	Limit  int64
+	Cursor *Cursor
+}
+
+// PageResult a slice of the articles list with cursors to the neighbour pages.
+type PageResult struct {
+	Articles   []entity.Article
+	TotalItems int64
+	Next       *Cursor
+	Prev       *Cursor
+}
+
+type cursorToken struct {
+	P int64  `json:"p"`
+	I string `json:"i"`
+	B bool   `json:"b,omitempty"`
+}
+
+// Encode return opaque string representation of cursor.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(cursorToken{P: c.Published.UnixMilli(), I: c.ID, B: c.Before}) //nolint:errchkjson
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parse cursor made by Cursor.Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var t cursorToken
+	if err = json.Unmarshal(b, &t); err != nil || t.I == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{Published: time.UnixMilli(t.P).UTC(), ID: t.I, Before: t.B}, nil
+}
+
+// Size return page limit in allowed range.
+func (p Page) Size() int64 {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageLimit
+	case p.Limit > MaxPageLimit:
+		return MaxPageLimit
+	default:
+		return p.Limit
+	}
+}
+
+// cursorAt make cursor that points to article.
+func cursorAt(a entity.Article, before bool) *Cursor {
+	return &Cursor{Published: a.Published, ID: a.ID, Before: before}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/entity"
+)
+
+// SearchHit article found by full text search with its relevance.
+type SearchHit struct {
+	entity.Article `bson:",inline"`
+	Score          float64 `bson:"score"`
+}
+
+// SearchResult page of search hits sorted by relevance.
+type SearchResult struct {
+	Hits       []SearchHit
+	TotalItems int64
+}
+
+// SearchTeamNews full text search over team articles title, teaser and content.
+// page starts from 1.
+func (r *Repository) SearchTeamNews(ctx context.Context, team, query string, page, limit int64) (*SearchResult, error) {
+	filter := bson.D{
+		{Key: "teamId", Value: team},
+		notDeleted,
+		{Key: "$text", Value: bson.D{{Key: "$search", Value: query}}},
+	}
+
+	total, err := r.db.CountDocuments(ctx, filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		h     []SearchHit
+		skip  = (page - 1) * limit
+		score = bson.D{{Key: "$meta", Value: "textScore"}}
+	)
+	data, err := r.db.Find(
+		ctx,
+		filter,
+		&options.FindOptions{
+			Projection: bson.D{{Key: "score", Value: score}},
+			Skip:       &skip,
+			Limit:      &limit,
+			Sort: bson.D{
+				{Key: "score", Value: score},
+				{Key: "published", Value: -1},
+			},
+		},
+		&h,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResult{Hits: *data.(*[]SearchHit), TotalItems: total}, nil
+}
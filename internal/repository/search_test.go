@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.sport-news/internal/entity"
+	"reflect"
+	"testing"
+)
+
+func TestRepository_SearchTeamNews(t *testing.T) {
+	hits := []SearchHit{
+		{Article: entity.Article{ID: "1", TeamID: "t94", Title: "Leeds"}, Score: 10},
+		{Article: entity.Article{ID: "2", TeamID: "t94", Teaser: "leeds"}, Score: 5},
+	}
+	filter := bson.D{
+		{Key: "teamId", Value: "t94"},
+		notDeleted,
+		{Key: "$text", Value: bson.D{{Key: "$search", Value: "leeds"}}},
+	}
+
+	tests := []struct {
+		name    string
+		page    int64
+		skip    int64
+		want    *SearchResult
+		wantErr bool
+	}{
+		{name: "first page", page: 1, skip: 0, want: &SearchResult{Hits: hits, TotalItems: 12}},
+		{name: "third page", page: 3, skip: 10, want: &SearchResult{Hits: hits, TotalItems: 12}},
+		{name: "search error", page: 1, skip: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, d, ctx := setup(t)
+			var h []SearchHit
+			limit := int64(5)
+			score := bson.D{{Key: "$meta", Value: "textScore"}}
+
+			d.On("CountDocuments", ctx, filter, nil).Return(int64(12), nil)
+			c := d.On(
+				"Find",
+				ctx,
+				filter,
+				&options.FindOptions{
+					Projection: bson.D{{Key: "score", Value: score}},
+					Skip:       &tt.skip,
+					Limit:      &limit,
+					Sort: bson.D{
+						{Key: "score", Value: score},
+						{Key: "published", Value: -1},
+					},
+				},
+				&h,
+			)
+			if tt.wantErr {
+				c.Return(nil, fmt.Errorf("some error"))
+			} else {
+				c.Return(&hits, nil)
+			}
+
+			got, err := r.SearchTeamNews(ctx, "t94", "leeds", tt.page, limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchTeamNews() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchTeamNews() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Package requestid keeps id of the API request in the context.
+package requestid
+
+import "context"
+
+type key string
+
+const (
+	keyID key = "requestId"
+	// Header of the request id, it's taken from the request and set to the response.
+	Header = "X-Request-ID"
+)
+
+// CtxWithID puts passed request id into the context.
+func CtxWithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, keyID, id)
+}
+
+// IDFromCtx returns request id, if any, previously
+// put in the context with CtxWithID.
+func IDFromCtx(ctx context.Context) string {
+	v, _ := ctx.Value(keyID).(string)
+
+	return v
+}
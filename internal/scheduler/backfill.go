@@ -0,0 +1,316 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	"go.sport-news/internal/entity"
+	"go.sport-news/internal/feed"
+	"go.sport-news/internal/repository"
+	"go.uber.org/zap"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backfill re-fetch details of the stored articles and save them mapped the same way as task does.
+// Articles are walked in batches by external id, the progress is saved after every batch,
+// so a stopped backfill goes on from the last batch. Dry run only logs changed fields.
+// Only InCrowd has a details endpoint, other sources are skipped.
+func Backfill(ctx context.Context, logger *zap.Logger, cfg config.Parser, bf config.Backfill, db database.DB) error {
+	sources, err := cfg.FeedSources()
+	if err != nil {
+		return err
+	}
+
+	opts := feed.NewOptions(cfg, feed.MustLoad(logger, cfg))
+	var found bool
+	for _, src := range sources {
+		if bf.Team != "" && src.Team != bf.Team {
+			continue
+		}
+		found = true
+
+		l := logger.With(zap.String("team", src.Team), zap.Bool("dryRun", bf.DryRun != 0))
+		if src.Provider != feed.InCrowd && src.Provider != "" {
+			l.Info("skip source without details", zap.String("provider", src.Provider))
+			continue
+		}
+
+		provider, err := feed.New(l, src, opts)
+		if err != nil {
+			return err
+		}
+
+		if err = backfill(
+			ctx,
+			l,
+			cfg,
+			bf,
+			src.Team,
+			provider,
+			repository.NewNewsRepository(db),
+			repository.NewBackfillRepository(db),
+		); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf("team %q is not in the feed sources", bf.Team)
+	}
+
+	return nil
+}
+
+func backfill(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg config.Parser,
+	bf config.Backfill,
+	team string,
+	provider feed.Provider,
+	rep repository.NewsRepository,
+	progress repository.BackfillRepository,
+) error {
+	dryRun := bf.DryRun != 0
+	p := entity.BackfillProgress{
+		ID:        progressID(team, bf.ExternalIds),
+		TeamID:    team,
+		StartedAt: time.Now().UTC(),
+	}
+
+	// a finished backfill starts again
+	if !dryRun && bf.Restart == 0 {
+		stored, err := progress.GetProgress(ctx, p.ID)
+		switch {
+		case errors.Is(err, repository.ErrProgressNotFound):
+		case err != nil:
+			return err
+		case stored.FinishedAt == nil:
+			p = *stored
+			logger.Info("resume backfill", zap.Int("after", p.LastExternalId), zap.Int("processed", p.Processed))
+		}
+	}
+
+	size := int64(max(bf.BatchSize, 1))
+	save := func() error {
+		p.Failed = len(p.FailedIds)
+		p.UpdatedAt = time.Now().UTC()
+		if dryRun {
+			return nil
+		}
+
+		return progress.SaveProgress(ctx, p)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch, err := rep.GetArticlesAfter(ctx, team, p.LastExternalId, bf.ExternalIds, size)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		failed, err := refetch(ctx, logger, cfg, dryRun, provider, rep, &p, batch)
+		if err != nil {
+			return err
+		}
+		p.Processed += len(batch)
+		p.FailedIds = append(p.FailedIds, failed...)
+		p.LastExternalId = batch[len(batch)-1].ExternalId
+
+		if err = save(); err != nil {
+			return err
+		}
+		logger.Info("backfill batch done", zap.Int("lastExternalId", p.LastExternalId), zap.Int("processed", p.Processed))
+	}
+
+	// failed articles of this and the resumed runs are retried once,
+	// the ones which fail again stay in the progress
+	var (
+		retry  = p.FailedIds
+		failed []int
+	)
+	for len(retry) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ids := retry[:min(len(retry), int(size))]
+		batch, err := rep.GetArticlesAfter(ctx, team, 0, ids, size)
+		if err != nil {
+			return err
+		}
+
+		f, err := refetch(ctx, logger, cfg, dryRun, provider, rep, &p, batch)
+		if err != nil {
+			return err
+		}
+		failed = append(failed, f...)
+		retry = retry[len(ids):]
+
+		// ids which aren't retried yet are kept when the run stops
+		p.FailedIds = append(slices.Clone(failed), retry...)
+		if err = save(); err != nil {
+			return err
+		}
+		logger.Info("backfill retry done", zap.Ints("externalIds", ids), zap.Ints("failed", f))
+	}
+
+	finished := time.Now().UTC()
+	p.FinishedAt = &finished
+	if err := save(); err != nil {
+		return err
+	}
+
+	logger.Info(
+		"backfill done",
+		zap.Int("processed", p.Processed),
+		zap.Int("changed", p.Changed),
+		zap.Int64("upserted", p.Upserted),
+		zap.Int64("retracted", p.Retracted),
+		zap.Int("failed", p.Failed),
+	)
+
+	return nil
+}
+
+// refetch get details of the batch, save the changed articles and retract unpublished ones,
+// dry run only logs them. Returns external ids which failed to fetch.
+func refetch(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg config.Parser,
+	dryRun bool,
+	provider feed.Provider,
+	rep repository.NewsRepository,
+	p *entity.BackfillProgress,
+	batch []entity.Article,
+) ([]int, error) {
+	stored := make(map[int]entity.Article, len(batch))
+	items := make([]feed.Item, 0, len(batch))
+	for _, a := range batch {
+		stored[a.ExternalId] = a
+		items = append(items, feed.Item{Article: a, IsPublished: true})
+	}
+
+	res := fetch(ctx, logger, cfg.Workers, provider, items)
+	failed := make([]int, 0, len(res.errors))
+	for _, e := range res.errors {
+		failed = append(failed, e.ExternalId)
+	}
+	slices.Sort(failed)
+
+	var changed []entity.Article
+	for _, a := range res.articles {
+		fields, err := changes(stored[a.ExternalId], a)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		changed = append(changed, a)
+
+		if dryRun {
+			logger.Info("article would change", zap.Int("externalId", a.ExternalId), zap.Strings("fields", fields))
+		}
+	}
+	p.Changed += len(changed)
+
+	if dryRun {
+		if len(res.unpublished) > 0 {
+			logger.Info("articles would be retracted", zap.Ints("externalIds", res.unpublished))
+		}
+		return failed, nil
+	}
+
+	if len(changed) > 0 {
+		n, err := rep.UpsertArticles(ctx, changed)
+		if err != nil {
+			return nil, err
+		}
+		p.Upserted += n
+	}
+
+	n, err := rep.RetractArticles(ctx, p.TeamID, res.unpublished, entity.DeleteReasonUnpublished)
+	if err != nil {
+		return nil, err
+	}
+	p.Retracted += n
+
+	return failed, nil
+}
+
+// progressID id of the backfill progress, backfills of different external ids don't share it.
+func progressID(team string, ids []int) string {
+	if len(ids) == 0 {
+		return team
+	}
+
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	s := make([]string, 0, len(sorted))
+	for _, id := range sorted {
+		s = append(s, strconv.Itoa(id))
+	}
+	sum := sha1.Sum([]byte(strings.Join(s, ","))) //nolint:gosec
+
+	return team + ":" + hex.EncodeToString(sum[:4])
+}
+
+// bookkeeping fields of the article which aren't compared by changes,
+// the stored id is kept by the upsert and lastSeen is set by the scheduler.
+var bookkeeping = map[string]bool{"id": true, "lastSeen": true, "deletedAt": true, "deleteReason": true}
+
+// changes return stored fields of the article which differ, bookkeeping fields are skipped.
+func changes(before, after entity.Article) ([]string, error) {
+	o, err := bson.Marshal(before)
+	if err != nil {
+		return nil, err
+	}
+	n, err := bson.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	ne, err := bson.Raw(n).Elements()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range ne {
+		if bookkeeping[e.Key()] {
+			continue
+		}
+		if v, err := bson.Raw(o).LookupErr(e.Key()); err != nil || !v.Equal(e.Value()) {
+			fields = append(fields, e.Key())
+		}
+	}
+
+	oe, err := bson.Raw(o).Elements()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range oe {
+		if bookkeeping[e.Key()] {
+			continue
+		}
+		if _, err := bson.Raw(n).LookupErr(e.Key()); err != nil {
+			fields = append(fields, e.Key())
+		}
+	}
+
+	return fields, nil
+}
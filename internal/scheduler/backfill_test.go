@@ -0,0 +1,210 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/entity"
+	"go.sport-news/internal/feed"
+	"go.sport-news/internal/repository/mocks"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// provider returns the stored article with a new subtitle, 3 is unpublished.
+type provider struct{}
+
+func (provider) List(context.Context) ([]feed.Item, error) { return nil, nil }
+
+func (provider) Forget() {}
+
+func (provider) Article(_ context.Context, item feed.Item) (entity.Article, error) {
+	if item.Article.ExternalId == 3 {
+		return entity.Article{}, feed.ErrUnpublished
+	}
+	a := item.Article
+	a.Subtitle = "subtitle"
+
+	return a, nil
+}
+
+func stored(id int) entity.Article {
+	return entity.Article{
+		ID:         entity.ArticleID("t94", id),
+		TeamID:     "t94",
+		ExternalId: id,
+		Subtitle:   "subtitle",
+		Published:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	rep := mocks.NewNewsRepository(t)
+	progress := mocks.NewBackfillRepository(t)
+
+	old := stored(2)
+	old.Subtitle = ""
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 1, []int(nil), int64(2)).
+		Return([]entity.Article{old, stored(3)}, nil)
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 3, []int(nil), int64(2)).
+		Return([]entity.Article{stored(4)}, nil)
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 4, []int(nil), int64(2)).
+		Return([]entity.Article{}, nil)
+
+	// only the changed article is saved
+	rep.On("UpsertArticles", mock.Anything, []entity.Article{stored(2)}).Return(int64(1), nil)
+	rep.On("RetractArticles", mock.Anything, "t94", []int{3}, entity.DeleteReasonUnpublished).Return(int64(1), nil)
+	rep.On("RetractArticles", mock.Anything, "t94", []int(nil), entity.DeleteReasonUnpublished).Return(int64(0), nil)
+
+	// resumed after the first article
+	progress.On("GetProgress", mock.Anything, "t94").
+		Return(&entity.BackfillProgress{ID: "t94", TeamID: "t94", LastExternalId: 1, Processed: 1}, nil)
+	var last entity.BackfillProgress
+	progress.On("SaveProgress", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { last = args.Get(1).(entity.BackfillProgress) }).
+		Return(nil)
+
+	err := backfill(
+		context.Background(),
+		zap.NewNop(),
+		config.Parser{Workers: 2},
+		config.Backfill{BatchSize: 2},
+		"t94",
+		provider{},
+		rep,
+		progress,
+	)
+	assert.NoError(t, err)
+
+	progress.AssertNumberOfCalls(t, "SaveProgress", 3)
+	assert.Equal(t, 4, last.LastExternalId)
+	assert.Equal(t, 4, last.Processed)
+	assert.Equal(t, 1, last.Changed)
+	assert.Equal(t, int64(1), last.Upserted)
+	assert.Equal(t, int64(1), last.Retracted)
+	assert.NotNil(t, last.FinishedAt)
+}
+
+// flaky provider fails the first fetch of every article.
+type flaky struct {
+	provider
+	fetched map[int]bool
+}
+
+func (f flaky) Article(ctx context.Context, item feed.Item) (entity.Article, error) {
+	if !f.fetched[item.Article.ExternalId] {
+		f.fetched[item.Article.ExternalId] = true
+		return entity.Article{}, fmt.Errorf("timeout")
+	}
+
+	return f.provider.Article(ctx, item)
+}
+
+func TestBackfill_RetryFailed(t *testing.T) {
+	rep := mocks.NewNewsRepository(t)
+	progress := mocks.NewBackfillRepository(t)
+
+	old := stored(2)
+	old.Subtitle = ""
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 0, []int(nil), int64(100)).Return([]entity.Article{old}, nil)
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 2, []int(nil), int64(100)).Return([]entity.Article{}, nil)
+	// failed articles of the resumed run are retried too
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 0, []int{1, 2}, int64(100)).Return([]entity.Article{stored(1), old}, nil)
+	rep.On("UpsertArticles", mock.Anything, []entity.Article{stored(2)}).Return(int64(1), nil)
+	rep.On("RetractArticles", mock.Anything, "t94", []int(nil), entity.DeleteReasonUnpublished).Return(int64(0), nil)
+
+	progress.On("GetProgress", mock.Anything, "t94").
+		Return(&entity.BackfillProgress{ID: "t94", TeamID: "t94", FailedIds: []int{1}}, nil)
+	var saved []entity.BackfillProgress
+	progress.On("SaveProgress", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(1).(entity.BackfillProgress)) }).
+		Return(nil)
+
+	err := backfill(
+		context.Background(),
+		zap.NewNop(),
+		config.Parser{},
+		config.Backfill{BatchSize: 100},
+		"t94",
+		flaky{fetched: map[int]bool{1: true}},
+		rep,
+		progress,
+	)
+	assert.NoError(t, err)
+
+	// the batch is saved with its failure, the retry clears both
+	if assert.Len(t, saved, 3) {
+		assert.Equal(t, []int{1, 2}, saved[0].FailedIds)
+		assert.Equal(t, 2, saved[0].Failed)
+		assert.Empty(t, saved[2].FailedIds)
+		assert.Equal(t, 0, saved[2].Failed)
+		assert.Equal(t, 1, saved[2].Changed)
+		assert.NotNil(t, saved[2].FinishedAt)
+	}
+}
+
+func TestBackfill_DryRun(t *testing.T) {
+	rep := mocks.NewNewsRepository(t)
+	progress := mocks.NewBackfillRepository(t)
+
+	old := stored(2)
+	old.Subtitle = ""
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 0, []int{2}, int64(100)).Return([]entity.Article{old}, nil)
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 2, []int{2}, int64(100)).Return([]entity.Article{}, nil)
+
+	err := backfill(
+		context.Background(),
+		zap.NewNop(),
+		config.Parser{},
+		config.Backfill{ExternalIds: []int{2}, BatchSize: 100, DryRun: 1},
+		"t94",
+		provider{},
+		rep,
+		progress,
+	)
+	assert.NoError(t, err)
+}
+
+func TestBackfill_Restart(t *testing.T) {
+	rep := mocks.NewNewsRepository(t)
+	progress := mocks.NewBackfillRepository(t)
+
+	// a finished progress isn't resumed
+	finished := time.Now()
+	progress.On("GetProgress", mock.Anything, "t94").
+		Return(&entity.BackfillProgress{ID: "t94", LastExternalId: 9, FinishedAt: &finished}, nil)
+	progress.On("SaveProgress", mock.Anything, mock.Anything).Return(nil)
+	rep.On("GetArticlesAfter", mock.Anything, "t94", 0, []int(nil), int64(1)).Return([]entity.Article{}, nil)
+
+	err := backfill(context.Background(), zap.NewNop(), config.Parser{}, config.Backfill{}, "t94", provider{}, rep, progress)
+	assert.NoError(t, err)
+}
+
+func TestProgressID(t *testing.T) {
+	assert.Equal(t, "t94", progressID("t94", nil))
+	assert.Equal(t, progressID("t94", []int{2, 1}), progressID("t94", []int{1, 2}))
+	assert.NotEqual(t, progressID("t94", []int{1}), progressID("t94", []int{1, 2}))
+}
+
+func TestChanges(t *testing.T) {
+	a := stored(1)
+	fields, err := changes(a, a)
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+
+	b := a
+	b.Subtitle, b.Title = "", "title"
+	fields, err = changes(a, b)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"subtitle", "title"}, fields)
+
+	// legacy id and bookkeeping fields aren't changes
+	c := a
+	c.ID, c.LastSeen = "legacy", time.Now()
+	fields, err = changes(c, a)
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+}
@@ -0,0 +1,129 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	scheduler "go.sport-news/internal/scheduler"
+)
+
+// Manager is an autogenerated mock type for the Manager type
+type Manager struct {
+	mock.Mock
+}
+
+// Job provides a mock function with given fields: team
+func (_m *Manager) Job(team string) (scheduler.JobState, error) {
+	ret := _m.Called(team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Job")
+	}
+
+	var r0 scheduler.JobState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (scheduler.JobState, error)); ok {
+		return rf(team)
+	}
+	if rf, ok := ret.Get(0).(func(string) scheduler.JobState); ok {
+		r0 = rf(team)
+	} else {
+		r0 = ret.Get(0).(scheduler.JobState)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(team)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Jobs provides a mock function with given fields:
+func (_m *Manager) Jobs() []scheduler.JobState {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Jobs")
+	}
+
+	var r0 []scheduler.JobState
+	if rf, ok := ret.Get(0).(func() []scheduler.JobState); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]scheduler.JobState)
+		}
+	}
+
+	return r0
+}
+
+// Pause provides a mock function with given fields: team
+func (_m *Manager) Pause(team string) error {
+	ret := _m.Called(team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pause")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(team)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resume provides a mock function with given fields: team
+func (_m *Manager) Resume(team string) error {
+	ret := _m.Called(team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(team)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RunNow provides a mock function with given fields: team
+func (_m *Manager) RunNow(team string) error {
+	ret := _m.Called(team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunNow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(team)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewManager creates a new instance of Manager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Manager {
+	mock := &Manager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
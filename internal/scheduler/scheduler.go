@@ -1,236 +1,506 @@
 package scheduler
 
 import (
-	"bytes"
 	"context"
-	"encoding/xml"
 	"errors"
 	"fmt"
-	cloudflarebp "github.com/DaRealFreak/cloudflare-bp-go"
 	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.sport-news/internal/config"
+	"go.sport-news/internal/content"
 	"go.sport-news/internal/database"
 	"go.sport-news/internal/entity"
+	"go.sport-news/internal/feed"
+	"go.sport-news/internal/metrics"
 	"go.sport-news/internal/repository"
-	"go.uber.org/multierr"
+	"go.sport-news/internal/tracing"
 	"go.uber.org/zap"
-	"io"
-	"net/http"
 	"sync"
 	"time"
 )
 
-type NewsItem struct {
-	ArticleURL        string `xml:"ArticleURL"`
-	NewsArticleID     int    `xml:"NewsArticleID"`
-	PublishDate       string `xml:"PublishDate"`
-	Taxonomies        string `xml:"Taxonomies"`
-	TeaserText        string `xml:"TeaserText"`
-	ThumbnailImageURL string `xml:"ThumbnailImageURL"`
-	Title             string `xml:"Title"`
-	OptaMatchId       string `xml:"OptaMatchId"`
-	LastUpdateDate    string `xml:"LastUpdateDate"`
-	IsPublished       string `xml:"IsPublished"`
-}
-type News struct {
-	XMLName             xml.Name `xml:"NewListInformation"`
-	ClubName            string   `xml:"ClubName"`
-	ClubWebsiteURL      string   `xml:"ClubWebsiteURL"`
-	NewsletterNewsItems struct {
-		NewsletterNewsItem []NewsItem `xml:"NewsletterNewsItem"`
-	} `xml:"NewsletterNewsItems"`
-}
-type NewsArticleInformation struct {
-	XMLName        xml.Name `xml:"NewsArticleInformation"`
-	ClubName       string   `xml:"ClubName"`
-	ClubWebsiteURL string   `xml:"ClubWebsiteURL"`
-	NewsArticle    struct {
-		ArticleURL        string `xml:"ArticleURL"`
-		NewsArticleID     int    `xml:"NewsArticleID"`
-		PublishDate       string `xml:"PublishDate"`
-		Taxonomies        string `xml:"Taxonomies"`
-		TeaserText        string `xml:"TeaserText"`
-		Subtitle          string `xml:"Subtitle"`
-		ThumbnailImageURL string `xml:"ThumbnailImageURL"`
-		Title             string `xml:"Title"`
-		BodyText          string `xml:"BodyText"`
-		GalleryImageURLs  string `xml:"GalleryImageURLs"`
-		VideoURL          string `xml:"VideoURL"`
-		OptaMatchId       string `xml:"OptaMatchId"`
-		LastUpdateDate    string `xml:"LastUpdateDate"`
-		IsPublished       string `xml:"IsPublished"`
-	} `xml:"NewsArticle"`
-}
-
-func New(logger *zap.Logger, cfg config.Parser, db database.DB) gocron.Job {
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobPaused   = errors.New("job is paused")
+	// ErrJobRunning returned by RunNow while the job runs, the overlapping run would be skipped.
+	ErrJobRunning = errors.New("job is running")
+)
+
+// Manager controls the jobs of the feed sources.
+type Manager interface {
+	Jobs() []JobState
+	Job(team string) (JobState, error)
+	RunNow(team string) error
+	Pause(team string) error
+	Resume(team string) error
+}
+
+// JobState of the source job, NextRun is empty while the job is paused.
+type JobState struct {
+	Team     string     `json:"team"`
+	Provider string     `json:"provider"`
+	Paused   bool       `json:"paused"`
+	NextRun  *time.Time `json:"nextRun"`
+	LastRun  *time.Time `json:"lastRun"`
+}
+
+type Scheduler struct {
+	logger *zap.Logger
+	s      gocron.Scheduler
+	teams  []string
+	jobs   map[string]*job
+}
+
+// job of one source, paused job skips its scheduled runs.
+type job struct {
+	gocron.Job
+	provider string
+
+	mu      sync.Mutex
+	paused  bool
+	running bool
+	lastRun time.Time
+}
+
+// New start scheduler with a job for every feed source.
+func New(logger *zap.Logger, cfg config.Parser, db database.DB) *Scheduler {
+	sources, err := cfg.FeedSources()
+	if err != nil {
+		logger.Fatal("invalid feed sources", zap.Error(err))
+	}
+
 	s, err := gocron.NewScheduler()
 	if err != nil {
 		logger.Fatal("failed init scheduler", zap.Error(err))
 	}
 
-	j, err := s.NewJob(
-		gocron.DurationJob(
-			cfg.JobTime,
-		),
-		gocron.NewTask(
-			task,
-			logger,
-			cfg,
-			db,
-		),
-	)
-	if err != nil {
-		logger.Fatal("failed register job", zap.Error(err))
+	// one fetcher for all sources, so connections and rate limits are shared
+	opts := feed.NewOptions(cfg, feed.MustLoad(logger, cfg))
+	sch := &Scheduler{
+		logger: logger,
+		s:      s,
+		teams:  make([]string, 0, len(sources)),
+		jobs:   make(map[string]*job, len(sources)),
 	}
+	for _, src := range sources {
+		l := logger.With(zap.String("team", src.Team))
 
-	logger.Info("register job", zap.String("uuid", j.ID().String()))
+		provider, err := feed.New(l, src, opts)
+		if err != nil {
+			logger.Fatal("failed init feed provider", zap.Error(err), zap.String("team", src.Team))
+		}
+
+		jb := &job{provider: src.Provider}
+		if jb.provider == "" {
+			jb.provider = feed.InCrowd
+		}
+		jb.Job, err = s.NewJob(
+			gocron.DurationJob(
+				src.JobTime,
+			),
+			gocron.NewTask(
+				jb.run,
+				l,
+				func() { task(l, cfg, src, provider, db) },
+			),
+			gocron.WithName(src.Team),
+			// manual runs don't overlap the scheduled ones
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			logger.Fatal("failed register job", zap.Error(err), zap.String("team", src.Team))
+		}
+
+		logger.Info("register job", zap.String("uuid", jb.ID().String()), zap.String("team", src.Team))
+		sch.teams = append(sch.teams, src.Team)
+		sch.jobs[src.Team] = jb
+	}
 	s.Start()
 
-	return j
+	return sch
+}
+
+// Jobs return states of all jobs in order of the sources.
+func (s *Scheduler) Jobs() []JobState {
+	res := make([]JobState, 0, len(s.teams))
+	for _, team := range s.teams {
+		res = append(res, s.jobs[team].state())
+	}
+
+	return res
+}
+
+// Job return state of the team job.
+func (s *Scheduler) Job(team string) (JobState, error) {
+	j, ok := s.jobs[team]
+	if !ok {
+		return JobState{}, ErrJobNotFound
+	}
+
+	return j.state(), nil
+}
+
+// RunNow run the team job at once, the schedule isn't changed.
+// The running job returns ErrJobRunning, because the scheduler skips overlapping runs.
+func (s *Scheduler) RunNow(team string) error {
+	j, ok := s.jobs[team]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if j.isPaused() {
+		return ErrJobPaused
+	}
+	if j.isRunning() {
+		return ErrJobRunning
+	}
+
+	s.logger.Info("run job now", zap.String("team", team))
+
+	return j.RunNow()
+}
+
+// Pause stop scheduled runs of the team job until Resume.
+func (s *Scheduler) Pause(team string) error {
+	return s.setPaused(team, true)
+}
+
+// Resume the paused team job.
+func (s *Scheduler) Resume(team string) error {
+	return s.setPaused(team, false)
+}
+
+// Shutdown stop the scheduler and wait for the running jobs.
+func (s *Scheduler) Shutdown() error {
+	return s.s.Shutdown()
+}
+
+func (s *Scheduler) setPaused(team string, paused bool) error {
+	j, ok := s.jobs[team]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	j.mu.Lock()
+	j.paused = paused
+	j.mu.Unlock()
+
+	s.logger.Info("job paused", zap.String("team", team), zap.Bool("paused", paused))
+
+	return nil
+}
+
+// run the task unless the job is paused.
+func (j *job) run(logger *zap.Logger, fn func()) {
+	j.mu.Lock()
+	paused := j.paused
+	if !paused {
+		j.lastRun = time.Now().UTC()
+		j.running = true
+	}
+	j.mu.Unlock()
+
+	if paused {
+		logger.Info("job is paused, run skipped")
+		return
+	}
+	defer func() {
+		j.mu.Lock()
+		j.running = false
+		j.mu.Unlock()
+	}()
+	fn()
+}
+
+func (j *job) isPaused() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.paused
+}
+
+func (j *job) isRunning() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.running
+}
+
+func (j *job) state() JobState {
+	j.mu.Lock()
+	st := JobState{Team: j.Name(), Provider: j.provider, Paused: j.paused}
+	if !j.lastRun.IsZero() {
+		last := j.lastRun
+		st.LastRun = &last
+	}
+	j.mu.Unlock()
+
+	if next, err := j.NextRun(); err == nil && !next.IsZero() && !st.Paused {
+		next = next.UTC()
+		st.NextRun = &next
+	}
+
+	return st
 }
 
 // task for scheduler
-// he goes to http and parse xml files.
-func task(logger *zap.Logger, cfg config.Parser, db database.DB) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+// he gets the feed list and fetches new and changed articles,
+// every run is recorded to the ingest runs.
+func task(logger *zap.Logger, cfg config.Parser, src config.Source, provider feed.Provider, db database.DB) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if cfg.RunTimeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), cfg.RunTimeout)
+	}
 	defer cancel()
 
-	rep := repository.NewNewsRepository(db)
-	oldIds, err := rep.GetAllExternalIds(ctx)
-	if err != nil {
-		logger.Error("failed get GetAllExternalIds", zap.Error(err))
-		return
+	runs := repository.NewIngestRepository(db)
+	run := entity.IngestRun{
+		ID:        uuid.NewString(),
+		TeamID:    src.Team,
+		Provider:  src.Provider,
+		StartedAt: time.Now().UTC(),
+		Status:    entity.IngestRunning,
+	}
+	if run.Provider == "" {
+		run.Provider = feed.InCrowd
 	}
 
-	var (
-		a   News
-		url = fmt.Sprintf("%s/getnewlistinformation?count=%d", cfg.URL, cfg.Count)
+	ctx, span := tracing.Start(
+		ctx,
+		"ingest.run",
+		trace.WithAttributes(
+			attribute.String("ingest.run", run.ID),
+			attribute.String("ingest.team", run.TeamID),
+			attribute.String("ingest.provider", run.Provider),
+		),
 	)
-	err = makeRequest(ctx, url, &a, 3)
+	logger = tracing.Logger(ctx, logger)
+
+	saveRun(ctx, logger, runs, run)
+	defer func() {
+		finished := time.Now().UTC()
+		run.FinishedAt = &finished
+		saveRun(ctx, logger, runs, run)
+		metrics.IngestRun(run)
+
+		span.SetAttributes(
+			attribute.String("ingest.status", run.Status),
+			attribute.Int("ingest.fetched", run.Fetched),
+			attribute.Int64("ingest.upserted", run.Upserted),
+			attribute.Int("ingest.failed", run.Failed),
+		)
+		var err error
+		if run.Status == entity.IngestFailed {
+			err = errors.New(run.Error)
+		}
+		tracing.End(span, err)
+	}()
+	fail := func(msg string, err error) {
+		logger.Error(msg, zap.Error(err))
+		run.Status = entity.IngestFailed
+		run.Error = fmt.Sprintf("%s: %s", msg, err)
+	}
+
+	// not modified feed doesn't touch the repository
+	items, err := provider.List(ctx)
+	if errors.Is(err, feed.ErrNotModified) {
+		logger.Debug("feed is not modified")
+		run.Status = entity.IngestNotModified
+		return
+	}
 	if err != nil {
-		logger.Error("failed do request", zap.Error(err), zap.String("url", url), zap.Any("data", a))
+		fail("failed get feed list", err)
 		return
 	}
+	run.Fetched = len(items)
 
-	newIds := make(map[int]NewsItem)
-	for _, item := range a.NewsletterNewsItems.NewsletterNewsItem {
-		_, ok := oldIds[item.NewsArticleID]
-		if !ok {
-			newIds[item.NewsArticleID] = item
+	// the next run gets the whole feed again unless this one is fully applied
+	var applied bool
+	defer func() {
+		if !applied {
+			provider.Forget()
 		}
-	}
-
-	wg := sync.WaitGroup{}
-	wg.Add(len(newIds))
-	mu := sync.Mutex{}
-	var addData []entity.Article
+	}()
 
-	for _, item := range newIds {
-		go func(wg *sync.WaitGroup, mu *sync.Mutex, item NewsItem) {
-			defer wg.Done()
+	rep := repository.NewNewsRepository(db)
+	oldIds, err := rep.GetAllExternalIds(ctx, src.Team)
+	if err != nil {
+		fail("failed get GetAllExternalIds", err)
+		return
+	}
 
-			t, err := time.Parse(time.DateTime, item.PublishDate)
-			if err != nil {
-				logger.Error("failed parse date", zap.Error(err), zap.String("data", item.PublishDate))
-				return
+	// new articles and stored ones which were edited or published again upstream
+	var (
+		changed   = make(map[int]feed.Item)
+		feedIds   []int
+		retracted []int
+		oldest    time.Time
+	)
+	for _, item := range items {
+		id := item.Article.ExternalId
+		state, ok := oldIds[id]
+		if !item.IsPublished {
+			if ok && state.DeletedAt == nil {
+				retracted = append(retracted, id)
 			}
+			continue
+		}
 
-			var (
-				aI  NewsArticleInformation
-				url = fmt.Sprintf("%s/getnewsarticleinformation?id=%d", cfg.URL, item.NewsArticleID)
-			)
-			if err = makeRequest(ctx, url, &aI, 0); err != nil {
-				logger.Error("failed do request", zap.Error(err), zap.String("url", url), zap.Any("data", aI))
-				return
-			}
+		feedIds = append(feedIds, id)
+		if oldest.IsZero() || item.Article.Published.Before(oldest) {
+			oldest = item.Article.Published
+		}
 
-			mu.Lock()
-			addData = append(addData, entity.Article{
-				ID:          uuid.New().String(),
-				TeamID:      entity.DefaultTeamId,
-				ExternalId:  item.NewsArticleID,
-				OptaMatchID: &item.OptaMatchId,
-				Title:       item.Title,
-				Type:        []string{item.Taxonomies},
-				Teaser:      item.TeaserText,
-				Content:     aI.NewsArticle.BodyText,
-				URL:         item.ArticleURL,
-				ImageURL:    item.ThumbnailImageURL,
-				GalleryUrls: aI.NewsArticle.GalleryImageURLs,
-				VideoURL:    aI.NewsArticle.VideoURL,
-				Published:   t,
-			})
-			mu.Unlock()
-		}(&wg, &mu, item)
+		switch {
+		case !ok, state.DeletedAt != nil, item.Article.Updated.After(state.Updated):
+			changed[id] = item
+		}
+	}
+
+	pending := make([]feed.Item, 0, len(changed))
+	for _, item := range changed {
+		pending = append(pending, item)
+	}
+	res := fetch(ctx, logger, cfg.Workers, provider, pending)
+	retracted = append(retracted, res.unpublished...)
+	run.Failed, run.Errors = len(res.errors), res.errors
+	addData := res.articles
+	// fetched articles are counted, unpublished and failed ones aren't saved
+	for i := range addData {
+		addData[i].LastSeen = time.Now().UTC()
+		if _, ok := oldIds[addData[i].ExternalId]; ok {
+			run.Updated++
+		} else {
+			run.New++
+		}
 	}
-	wg.Wait()
 
 	if len(addData) > 0 {
-		err = rep.InsertArticles(ctx, addData)
+		run.Upserted, err = rep.UpsertArticles(ctx, addData)
 		if err != nil {
-			logger.Error("failed add posts", zap.Error(err))
+			fail("failed upsert posts", err)
 			return
 		}
 	}
-	logger.Info("success done job", zap.Int("added posts", len(addData)))
-}
 
-// makeRequest do a http get request with retry.
-func makeRequest(ctx context.Context, url string, data any, retries int) error {
-	var (
-		response *http.Response
-		try      = 0
-		te       error
+	run.Retracted, err = retract(ctx, rep, cfg, src.Team, feedIds, retracted, oldest)
+	if err != nil {
+		fail("failed retract posts", err)
+		return
+	}
+
+	applied = run.Failed == 0
+	run.Status = entity.IngestSuccess
+
+	logger.Info(
+		"success done job",
+		zap.String("run", run.ID),
+		zap.Int("new posts", run.New),
+		zap.Int("updated posts", run.Updated),
+		zap.Int64("upserted posts", run.Upserted),
+		zap.Int64("retracted posts", run.Retracted),
+		zap.Int("failed posts", run.Failed),
 	)
-	timeout := time.Second * 20
+}
 
-	for try <= retries {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, bytes.NewBuffer(nil))
-		if err != nil {
-			te = multierr.Append(te, err)
-			try++
-			continue
-		}
+// fetched details of the feed items.
+type fetched struct {
+	articles    []entity.Article
+	unpublished []int
+	errors      []entity.IngestError
+}
 
-		c := &http.Client{Timeout: timeout}
-		c.Transport = cloudflarebp.AddCloudFlareByPass(c.Transport)
+// fetch get details of the items with a bounded pool of workers,
+// so the feed host isn't hit with every article at once.
+// Articles are mapped with prepare.
+func fetch(ctx context.Context, logger *zap.Logger, workers int, provider feed.Provider, items []feed.Item) fetched {
+	queue := make(chan feed.Item)
+	workers = min(max(workers, 1), max(len(items), 1))
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	mu := sync.Mutex{}
+	var res fetched
 
-		response, err = c.Do(req)
+	for range workers {
+		go func(wg *sync.WaitGroup, mu *sync.Mutex) {
+			defer wg.Done()
 
-		if response == nil || err != nil {
-			try++
-			continue
-		}
+			for item := range queue {
+				a, err := provider.Article(ctx, item)
+				if errors.Is(err, feed.ErrUnpublished) {
+					mu.Lock()
+					res.unpublished = append(res.unpublished, item.Article.ExternalId)
+					mu.Unlock()
+					continue
+				}
+				if err != nil {
+					logger.Error("failed get article", zap.Error(err), zap.Int("externalId", item.Article.ExternalId))
+					mu.Lock()
+					res.errors = append(res.errors, entity.IngestError{ExternalId: item.Article.ExternalId, Error: err.Error()})
+					mu.Unlock()
+					continue
+				}
+				prepare(&a)
 
-		switch response.StatusCode {
-		case http.StatusInternalServerError:
-			body, _ := io.ReadAll(response.Body)
-			te = multierr.Append(te, fmt.Errorf("%s", fmt.Sprintf("err[500],req:\n%s", body)))
-			response.Body.Close() //nolint:errcheck
-			time.Sleep(time.Second)
-			try++
-			continue
-		case http.StatusBadRequest:
-			body, _ := io.ReadAll(response.Body)
-			te = multierr.Append(te, fmt.Errorf("err[400],req:\n%s, data:\n%s", url, body))
-			response.Body.Close() //nolint:errcheck
-			return te
-		}
+				mu.Lock()
+				res.articles = append(res.articles, a)
+				mu.Unlock()
+			}
+		}(&wg, &mu)
+	}
+	for _, item := range items {
+		queue <- item
+	}
+	close(queue)
+	wg.Wait()
+
+	return res
+}
+
+// prepare map the feed article to the stored one.
+func prepare(a *entity.Article) {
+	a.ID = entity.ArticleID(a.TeamID, a.ExternalId)
+	content.Apply(a)
+}
 
-		break
+// saveRun store the run, the ingest itself goes on when it fails.
+// The run is stored even after the ingest timed out.
+func saveRun(ctx context.Context, logger *zap.Logger, runs repository.IngestRepository, run entity.IngestRun) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := runs.SaveRun(ctx, run); err != nil {
+		logger.Error("failed save ingest run", zap.Error(err), zap.String("run", run.ID))
+	}
+}
+
+// retract mark feed articles as seen and soft delete unpublished articles
+// and articles which have been missing from the feed for cfg.RetractAfter.
+// oldest is the publish date of the oldest article in the feed.
+func retract(
+	ctx context.Context,
+	rep repository.NewsRepository,
+	cfg config.Parser,
+	team string,
+	feedIds, unpublished []int,
+	oldest time.Time,
+) (int64, error) {
+	now := time.Now().UTC()
+	if err := rep.TouchArticles(ctx, team, feedIds, now); err != nil {
+		return 0, err
 	}
 
-	if response == nil {
-		return multierr.Append(te, fmt.Errorf("failed get response (empty response) in %s", http.MethodGet))
+	removed, err := rep.RetractArticles(ctx, team, unpublished, entity.DeleteReasonUnpublished)
+	if err != nil {
+		return removed, err
 	}
-	defer response.Body.Close() //nolint:errcheck
 
-	if err := xml.NewDecoder(response.Body).Decode(&data); err != nil {
-		body, _ := io.ReadAll(response.Body)
-		te = multierr.Append(te, errors.New(string(body)))
-		return multierr.Append(te, fmt.Errorf("failed decode response in %s, %w", url, err))
+	if cfg.RetractAfter <= 0 || oldest.IsZero() {
+		return removed, nil
 	}
 
-	return nil
+	missing, err := rep.RetractMissing(ctx, team, feedIds, oldest, now.Add(-cfg.RetractAfter))
+
+	return removed + missing, err
 }
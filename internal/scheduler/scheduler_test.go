@@ -0,0 +1,302 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.sport-news/internal/config"
+	"go.sport-news/internal/database"
+	dm "go.sport-news/internal/database/mocks"
+	"go.sport-news/internal/entity"
+	"go.sport-news/internal/feed"
+	"go.sport-news/internal/repository"
+	"go.uber.org/zap"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func setup(t *testing.T, runs *atomic.Int32) *Scheduler {
+	s, err := gocron.NewScheduler()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Shutdown() })
+
+	jb := &job{provider: "incrowd"}
+	jb.Job, err = s.NewJob(
+		gocron.DurationJob(time.Hour),
+		gocron.NewTask(jb.run, zap.NewNop(), func() { runs.Add(1) }),
+		gocron.WithName("t94"),
+	)
+	require.NoError(t, err)
+	s.Start()
+
+	return &Scheduler{logger: zap.NewNop(), s: s, teams: []string{"t94"}, jobs: map[string]*job{"t94": jb}}
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	var runs atomic.Int32
+	s := setup(t, &runs)
+
+	assert.NoError(t, s.RunNow("t94"))
+	assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	st, err := s.Job("t94")
+	assert.NoError(t, err)
+	assert.Equal(t, "t94", st.Team)
+	assert.False(t, st.Paused)
+	assert.NotNil(t, st.LastRun)
+	assert.NotNil(t, st.NextRun)
+
+	assert.ErrorIs(t, s.RunNow("t1"), ErrJobNotFound)
+}
+
+func TestScheduler_RunNowRunning(t *testing.T) {
+	var runs atomic.Int32
+	s := setup(t, &runs)
+
+	// the run in progress answers instead of skipping the overlapping one
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.jobs["t94"].run(zap.NewNop(), func() { <-release })
+	}()
+	assert.Eventually(t, s.jobs["t94"].isRunning, time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, s.RunNow("t94"), ErrJobRunning)
+
+	close(release)
+	<-done
+	assert.NoError(t, s.RunNow("t94"))
+	assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestScheduler_Pause(t *testing.T) {
+	var runs atomic.Int32
+	s := setup(t, &runs)
+
+	assert.NoError(t, s.Pause("t94"))
+	assert.ErrorIs(t, s.RunNow("t94"), ErrJobPaused)
+
+	st, _ := s.Job("t94")
+	assert.True(t, st.Paused)
+	assert.Nil(t, st.NextRun)
+
+	// scheduled runs are skipped
+	s.jobs["t94"].run(zap.NewNop(), func() { runs.Add(1) })
+	assert.Equal(t, int32(0), runs.Load())
+
+	assert.NoError(t, s.Resume("t94"))
+	assert.NoError(t, s.RunNow("t94"))
+	assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Len(t, s.Jobs(), 1)
+
+	assert.ErrorIs(t, s.Pause("t1"), ErrJobNotFound)
+}
+
+// notModified provider answers 304 to the list.
+type notModified struct {
+	provider
+}
+
+func (notModified) List(context.Context) ([]feed.Item, error) { return nil, feed.ErrNotModified }
+
+func TestTask_NotModified(t *testing.T) {
+	// the articles aren't read, only the run is saved
+	db, runs := dm.NewDB(t), dm.NewDB(t)
+	db.On("WithCollection", database.IngestRuns).Return(runs)
+	var last entity.IngestRun
+	runs.On("UpdateOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { last = args.Get(2).(bson.D)[0].Value.(entity.IngestRun) }).
+		Return(int64(1), nil)
+
+	task(zap.NewNop(), config.Parser{}, config.Source{Team: "t94"}, notModified{}, db)
+
+	assert.Equal(t, entity.IngestNotModified, last.Status)
+	runs.AssertNumberOfCalls(t, "UpdateOne", 2)
+}
+
+// fakeFeed lists items, unpublished and failing articles answer with errors to the details.
+type fakeFeed struct {
+	items       []feed.Item
+	unpublished []int
+	failing     []int
+	forgot      bool
+}
+
+func (f *fakeFeed) List(context.Context) ([]feed.Item, error) { return f.items, nil }
+
+func (f *fakeFeed) Forget() { f.forgot = true }
+
+func (f *fakeFeed) Article(_ context.Context, item feed.Item) (entity.Article, error) {
+	switch id := item.Article.ExternalId; {
+	case slices.Contains(f.unpublished, id):
+		return entity.Article{}, feed.ErrUnpublished
+	case slices.Contains(f.failing, id):
+		return entity.Article{}, fmt.Errorf("timeout")
+	}
+
+	return item.Article, nil
+}
+
+// applied inputs of the news repository in one run.
+type applied struct {
+	upserted  []int
+	touched   []int
+	retracted []int
+	missing   []int
+	since     time.Time
+}
+
+// mockNews mock the articles collection with the stored states and record the writes.
+func mockNews(db *dm.DB, stored []repository.ExternalState) *applied {
+	var got applied
+
+	states := slices.Clone(stored)
+	db.On("Find", mock.Anything, bson.D{{Key: "teamId", Value: "t94"}}, mock.Anything, mock.Anything).
+		Return(&states, nil)
+	db.On("BulkUpsert", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, filters, _ []interface{}) (int64, error) {
+			for _, f := range filters {
+				got.upserted = append(got.upserted, f.(bson.D)[1].Value.(int))
+			}
+			return int64(len(filters)), nil
+		}).Maybe()
+	db.On("UpdateMany", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, filter, update, _ interface{}) (int64, error) {
+			f := filter.(bson.D)
+			ids := f[1].Value.(bson.D)[0]
+			switch {
+			case update.(bson.D)[0].Value.(bson.D)[0].Key == "lastSeen":
+				got.touched = ids.Value.([]int)
+			case ids.Key == "$in":
+				got.retracted = ids.Value.([]int)
+				return int64(len(got.retracted)), nil
+			default:
+				got.missing = ids.Value.([]int)
+				got.since = f[2].Value.(bson.D)[0].Value.(time.Time)
+			}
+			return 0, nil
+		}).Maybe()
+
+	return &got
+}
+
+func TestTask(t *testing.T) {
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	edited := old.Add(time.Hour)
+	item := func(id int, published bool, updated time.Time) feed.Item {
+		return feed.Item{
+			Article: entity.Article{
+				TeamID:     "t94",
+				ExternalId: id,
+				Published:  old.Add(time.Duration(id) * time.Minute),
+				Updated:    updated,
+			},
+			IsPublished: published,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		cfg    config.Parser
+		stored []repository.ExternalState
+		feed   *fakeFeed
+		want   applied
+		run    entity.IngestRun
+	}{
+		{
+			name: "new, edited and published again articles are fetched",
+			stored: []repository.ExternalState{
+				{ExternalId: 1, Updated: old},
+				{ExternalId: 2, Updated: old},
+				{ExternalId: 3, Updated: old, DeletedAt: &old},
+			},
+			feed: &fakeFeed{items: []feed.Item{
+				item(1, true, old),
+				item(2, true, edited),
+				item(3, true, old),
+				item(4, true, old),
+			}},
+			want: applied{upserted: []int{2, 3, 4}, touched: []int{1, 2, 3, 4}},
+			run:  entity.IngestRun{New: 1, Updated: 2},
+		},
+		{
+			name: "stored articles unpublished in the list are retracted",
+			stored: []repository.ExternalState{
+				{ExternalId: 1, Updated: old},
+				{ExternalId: 2, Updated: old, DeletedAt: &old},
+			},
+			feed: &fakeFeed{items: []feed.Item{
+				item(1, false, old),
+				item(2, false, old),
+				item(3, false, old),
+				item(4, true, old),
+			}},
+			want: applied{upserted: []int{4}, touched: []int{4}, retracted: []int{1}},
+			run:  entity.IngestRun{New: 1},
+		},
+		{
+			name: "articles unpublished in the details are retracted",
+			feed: &fakeFeed{
+				items:       []feed.Item{item(1, true, old), item(2, true, old)},
+				unpublished: []int{2},
+			},
+			want: applied{upserted: []int{1}, touched: []int{1, 2}, retracted: []int{2}},
+			run:  entity.IngestRun{New: 1},
+		},
+		{
+			name:   "failed articles make the next run fetch the feed again",
+			stored: []repository.ExternalState{{ExternalId: 2, Updated: old}},
+			feed: &fakeFeed{
+				items:   []feed.Item{item(1, true, old), item(2, true, edited)},
+				failing: []int{2},
+			},
+			want: applied{upserted: []int{1}, touched: []int{1, 2}},
+			run:  entity.IngestRun{New: 1, Failed: 1},
+		},
+		{
+			name: "missing articles published since the oldest feed article are retracted",
+			cfg:  config.Parser{RetractAfter: time.Hour},
+			stored: []repository.ExternalState{
+				{ExternalId: 1, Updated: old},
+				{ExternalId: 2, Updated: old},
+			},
+			feed: &fakeFeed{items: []feed.Item{item(2, true, old), item(1, true, old)}},
+			want: applied{
+				touched: []int{2, 1},
+				missing: []int{2, 1},
+				since:   item(1, true, old).Article.Published,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, runs := dm.NewDB(t), dm.NewDB(t)
+			db.On("WithCollection", database.IngestRuns).Return(runs)
+			var last entity.IngestRun
+			runs.On("UpdateOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { last = args.Get(2).(bson.D)[0].Value.(entity.IngestRun) }).
+				Return(int64(1), nil)
+			got := mockNews(db, tt.stored)
+
+			task(zap.NewNop(), tt.cfg, config.Source{Team: "t94"}, tt.feed, db)
+
+			assert.Equal(t, entity.IngestSuccess, last.Status)
+			assert.ElementsMatch(t, tt.want.upserted, got.upserted)
+			assert.Equal(t, tt.want.touched, got.touched)
+			assert.ElementsMatch(t, tt.want.retracted, got.retracted)
+			assert.Equal(t, tt.want.missing, got.missing)
+			assert.Equal(t, tt.want.since, got.since)
+			assert.Equal(t, int64(len(tt.want.upserted)), last.Upserted)
+			assert.Equal(t, tt.run.New, last.New)
+			assert.Equal(t, tt.run.Updated, last.Updated)
+			assert.Equal(t, tt.run.Failed, last.Failed)
+			assert.Equal(t, tt.run.Failed > 0, tt.feed.forgot)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package tracing sets up OpenTelemetry and starts spans of the service.
+package tracing
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.sport-news/internal/config"
+	"go.uber.org/zap"
+)
+
+// Exporters of the spans.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+const tracerName = "go.sport-news"
+
+// MustLoad set the global tracer provider, spans are dropped with none exporter.
+// Returned shutdown flushes the spans.
+func MustLoad(ctx context.Context, logger *zap.Logger, cfg config.Tracing, version string) func(context.Context) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }
+	case ExporterStdout:
+		e, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			logger.Fatal("failed init stdout exporter", zap.Error(err))
+		}
+		exporter = e
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure != 0 {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		e, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			logger.Fatal("failed init otlp exporter", zap.Error(err))
+		}
+		exporter = e
+	default:
+		logger.Fatal("unknown tracing exporter", zap.String("exporter", cfg.Exporter))
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		logger.Fatal("failed init tracing resource", zap.Error(err))
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	logger.Info("tracing is on", zap.String("exporter", cfg.Exporter))
+
+	return tp.Shutdown
+}
+
+// Start span of the service tracer.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End the span, the error is recorded.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Fields trace and span ids of the context span for the logs.
+func Fields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.String("traceId", sc.TraceID().String()),
+		zap.String("spanId", sc.SpanID().String()),
+	}
+}
+
+// Logger with trace and span ids of the context span.
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if f := Fields(ctx); f != nil {
+		return logger.With(f...)
+	}
+
+	return logger
+}